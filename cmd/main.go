@@ -2,11 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,8 +18,11 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 	"k8s.io/klog/v2"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/leader"
+	"lightweight-descheduler/pkg/metrics"
 	"lightweight-descheduler/pkg/scheduler"
 )
 
@@ -25,16 +30,49 @@ var (
 	configPath  = flag.String("config", "", "Path to configuration file")
 	kubeconfig  = flag.String("kubeconfig", "", "Path to kubeconfig file (optional, defaults to in-cluster config)")
 	logLevel    = flag.String("log-level", "2", "Log level (0-5)")
+	metricsAddr = flag.String("metrics-addr", ":8080", "Address to serve Prometheus metrics on (empty to disable)")
+	once        = flag.Bool("once", false, "Run a single descheduling cycle and exit, same as mode: once in the configuration file")
+	configPoll  = flag.Duration("config-poll-interval", 10*time.Second, "Interval for checking the configuration file for changes (0 to reload only on SIGHUP)")
 	showVersion = flag.Bool("version", false, "Show version and exit")
 	showHelp    = flag.Bool("help", false, "Show help and exit")
 )
 
+// 选主相关参数
+var (
+	leaderElect          = flag.Bool("leader-elect", false, "Enable Lease-based leader election so only one replica runs descheduling cycles")
+	leaderElectLeaseName = flag.String("leader-elect-lease-name", appName, "Name of the Lease object used for leader election")
+	leaderElectNamespace = flag.String("leader-elect-namespace", "", "Namespace of the Lease object (defaults to $POD_NAMESPACE or kube-system)")
+	leaseDuration        = flag.Duration("leader-elect-lease-duration", 15*time.Second, "Duration that non-leader candidates wait before trying to acquire the lease")
+	renewDeadline        = flag.Duration("leader-elect-renew-deadline", 10*time.Second, "Duration the leader retries refreshing the lease before giving up leadership")
+	retryPeriod          = flag.Duration("leader-elect-retry-period", 2*time.Second, "Duration between leader election attempts")
+)
+
 const (
 	version = "v1.0.1"
 	appName = "lightweight-descheduler"
 )
 
+// 单次运行模式的退出码，CronJob据此判断Job是否成功
+const (
+	// exitSucceeded 所有策略执行成功
+	exitSucceeded = 0
+
+	// exitFailed 循环无法执行，或所有策略都执行失败
+	exitFailed = 1
+
+	// exitPartlyFailed 部分策略执行失败
+	exitPartlyFailed = 2
+
+	// exitLimitsReached 所有策略执行成功，但有Pod因达到驱逐限制没有驱逐
+	exitLimitsReached = 3
+)
+
 func main() {
+	// validate子命令只检查配置文件，不连接集群
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
+	}
+
 	// 初始化klog
 	klog.InitFlags(nil)
 	flag.Parse()
@@ -57,17 +95,23 @@ func main() {
 	klog.Infof("Starting %s %s", appName, version)
 
 	// 加载配置
-	cfg, err := loadConfig()
+	cfg, configFile, err := loadConfig()
 	if err != nil {
 		klog.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// 命令行参数优先于配置文件中的运行模式
+	if *once {
+		cfg.Mode = config.ModeOnce
+	}
+	singleCycle := cfg.Mode == config.ModeOnce
+
 	klog.Infof("Configuration loaded successfully")
-	klog.Infof("DryRun: %v, Interval: %v, LogLevel: %s",
-		cfg.DryRun, cfg.Interval, cfg.LogLevel)
+	klog.Infof("Mode: %s, DryRun: %v, Interval: %v, LogLevel: %s",
+		cfg.Mode, cfg.DryRun, cfg.Interval, cfg.LogLevel)
 
 	// 创建Kubernetes客户端
-	client, err := createKubernetesClient()
+	client, metricsClient, err := createKubernetesClients()
 	if err != nil {
 		klog.Fatalf("Failed to create kubernetes client: %v", err)
 	}
@@ -75,7 +119,7 @@ func main() {
 	klog.Infof("Kubernetes client created successfully")
 
 	// 创建调度器
-	sched, err := scheduler.NewScheduler(client, cfg)
+	sched, err := scheduler.NewScheduler(client, metricsClient, cfg)
 	if err != nil {
 		klog.Fatalf("Failed to create scheduler: %v", err)
 	}
@@ -93,17 +137,92 @@ func main() {
 		cancel()
 	}()
 
+	if singleCycle {
+		code := runSingleCycle(ctx, client, sched)
+		cancel()
+		klog.Flush()
+		os.Exit(code)
+	}
+
+	// 配置文件变化或收到SIGHUP时在两次循环之间重新加载配置
+	if err := sched.WatchConfig(configFile, *configPoll); err != nil {
+		klog.Fatalf("Failed to watch configuration file: %v", err)
+	}
+
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	go func() {
+		for range hupChan {
+			klog.Infof("Received SIGHUP, reloading configuration...")
+			sched.RequestReload()
+		}
+	}()
+
+	// 启动指标服务
+	if *metricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(ctx, *metricsAddr); err != nil {
+				klog.Errorf("Metrics server failed: %v", err)
+			}
+		}()
+	}
+
 	// 运行调度器
-	klog.Infof("Starting scheduler...")
-	if err := sched.Run(ctx); err != nil && err != context.Canceled {
+	if *leaderElect {
+		klog.Infof("Leader election enabled, waiting for leadership before starting scheduler...")
+		err = leader.Run(ctx, client, leaderElectionConfig(), sched.Run)
+	} else {
+		klog.Infof("Starting scheduler...")
+		err = sched.Run(ctx)
+	}
+	if err != nil && err != context.Canceled {
 		klog.Fatalf("Scheduler failed: %v", err)
 	}
 
 	klog.Infof("Scheduler stopped gracefully")
 }
 
-// loadConfig 加载配置文件
-func loadConfig() (*config.Config, error) {
+// runSingleCycle 执行一次重调度循环，根据循环结果返回退出码
+func runSingleCycle(ctx context.Context, client kubernetes.Interface, sched *scheduler.Scheduler) int {
+	var result *scheduler.CycleResult
+	run := func(ctx context.Context) error {
+		var err error
+		result, err = sched.RunOnce(ctx)
+		return err
+	}
+
+	var err error
+	if *leaderElect {
+		klog.Infof("Leader election enabled, waiting for leadership before running a single cycle...")
+		err = leader.Run(ctx, client, leaderElectionConfig(), run)
+	} else {
+		klog.Infof("Running a single descheduling cycle...")
+		err = run(ctx)
+	}
+	if err != nil {
+		klog.Errorf("Descheduling cycle failed: %v", err)
+		return exitFailed
+	}
+
+	switch {
+	case len(result.FailedStrategies) > 0 && len(result.FailedStrategies) == len(result.Strategies):
+		klog.Errorf("All strategies failed: %s", strings.Join(result.FailedStrategies, ", "))
+		return exitFailed
+	case len(result.FailedStrategies) > 0:
+		klog.Errorf("Strategies failed: %s", strings.Join(result.FailedStrategies, ", "))
+		return exitPartlyFailed
+	case result.RefusedByLimits > 0:
+		klog.Warningf("Eviction limits reached, %d pods were not evicted", result.RefusedByLimits)
+		return exitLimitsReached
+	}
+
+	klog.Infof("Descheduling cycle succeeded")
+	return exitSucceeded
+}
+
+// loadConfig 加载配置文件，同时返回实际使用的配置文件路径
+func loadConfig() (*config.Config, string, error) {
 	configFile := *configPath
 
 	// 如果没有指定配置文件，尝试默认位置
@@ -122,16 +241,66 @@ func loadConfig() (*config.Config, error) {
 		}
 
 		if configFile == "" {
-			return nil, fmt.Errorf("no configuration file found. Please specify with -config flag or place config.yaml in current directory")
+			return nil, "", fmt.Errorf("no configuration file found. Please specify with -config flag or place config.yaml in current directory")
 		}
 	}
 
 	klog.Infof("Loading configuration from: %s", configFile)
-	return config.LoadConfig(configFile)
+	cfg, err := config.LoadConfig(configFile)
+	return cfg, configFile, err
 }
 
-// createKubernetesClient 创建Kubernetes客户端
-func createKubernetesClient() (kubernetes.Interface, error) {
+// runValidate 检查配置文件并一次报告发现的所有问题，配置有效时返回0
+func runValidate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	path := flags.String("config", "", "Path to configuration file to validate")
+	flags.Parse(args)
+
+	if *path == "" {
+		fmt.Fprintf(os.Stderr, "Usage: %s validate -config <file>\n", appName)
+		return 2
+	}
+
+	if _, err := config.LoadConfig(*path); err != nil {
+		var validationErr *config.ValidationError
+		if !errors.As(err, &validationErr) {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", *path, err)
+			return 1
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: %d problem(s) found:\n", *path, len(validationErr.Problems))
+		for _, problem := range validationErr.Problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		return 1
+	}
+
+	fmt.Printf("%s: configuration is valid\n", *path)
+	return 0
+}
+
+// leaderElectionConfig 根据命令行参数构建选主配置
+func leaderElectionConfig() leader.ElectionConfig {
+	namespace := *leaderElectNamespace
+	if namespace == "" {
+		namespace = os.Getenv("POD_NAMESPACE")
+	}
+	if namespace == "" {
+		namespace = "kube-system"
+	}
+
+	return leader.ElectionConfig{
+		LeaseName:      *leaderElectLeaseName,
+		LeaseNamespace: namespace,
+		Identity:       os.Getenv("POD_NAME"),
+		LeaseDuration:  *leaseDuration,
+		RenewDeadline:  *renewDeadline,
+		RetryPeriod:    *retryPeriod,
+	}
+}
+
+// createKubernetesClients 创建Kubernetes客户端和metrics.k8s.io客户端
+func createKubernetesClients() (kubernetes.Interface, metricsclientset.Interface, error) {
 	var cfg *rest.Config
 	var err error
 
@@ -157,7 +326,7 @@ func createKubernetesClient() (kubernetes.Interface, error) {
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes config: %v", err)
+		return nil, nil, fmt.Errorf("failed to create kubernetes config: %v", err)
 	}
 
 	// 设置客户端配置
@@ -166,7 +335,13 @@ func createKubernetesClient() (kubernetes.Interface, error) {
 
 	client, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes client: %v", err)
+		return nil, nil, fmt.Errorf("failed to create kubernetes client: %v", err)
+	}
+
+	// metrics客户端只在使用metrics利用率来源时才会发起请求
+	metricsClient, err := metricsclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create metrics client: %v", err)
 	}
 
 	// 测试连接
@@ -175,10 +350,10 @@ func createKubernetesClient() (kubernetes.Interface, error) {
 
 	_, err = client.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to kubernetes cluster: %v", err)
+		return nil, nil, fmt.Errorf("failed to connect to kubernetes cluster: %v", err)
 	}
 
-	return client, nil
+	return client, metricsClient, nil
 }
 
 // printHelp 输出帮助信息
@@ -187,6 +362,7 @@ func printHelp() {
 
 用法:
   %s [选项]
+  %s validate -config <文件>    检查配置文件，报告所有问题后退出（不连接集群）
 
 选项:
   -config string
@@ -195,6 +371,25 @@ func printHelp() {
       kubeconfig 文件路径 (默认使用 in-cluster 配置或 ~/.kube/config)
   -log-level string
       日志级别 0-5 (默认: "2")
+  -metrics-addr string
+      Prometheus 指标监听地址，为空时禁用 (默认: ":8080")
+  -once
+      只执行一次重调度循环后退出，等同于配置文件中的 mode: once (默认: false)
+      退出码: 0 全部策略成功, 1 循环失败或全部策略失败, 2 部分策略失败, 3 达到驱逐限制
+  -config-poll-interval duration
+      检查配置文件变化的间隔，为 0 时只在收到 SIGHUP 时重新加载 (默认: 10s)
+  -leader-elect
+      启用基于 Lease 的选主，多副本部署时只有 Leader 执行重调度 (默认: false)
+  -leader-elect-lease-name string
+      选主使用的 Lease 名称 (默认: "lightweight-descheduler")
+  -leader-elect-namespace string
+      Lease 所在命名空间 (默认使用 $POD_NAMESPACE，否则为 kube-system)
+  -leader-elect-lease-duration duration
+      非 Leader 等待多久后尝试抢占 Lease (默认: 15s)
+  -leader-elect-renew-deadline duration
+      Leader 续约超时时间，超时后放弃领导权 (默认: 10s)
+  -leader-elect-retry-period duration
+      获取或续约 Lease 的重试间隔 (默认: 2s)
   -version
       显示版本信息
   -help
@@ -210,8 +405,17 @@ func printHelp() {
   # 指定kubeconfig和日志级别
   %s -kubeconfig ~/.kube/config -log-level 3
 
+  # 以 CronJob 方式运行，执行一次后退出
+  %s -once
+
+  # 多副本部署时启用选主
+  %s -leader-elect
+
+  # 在CI中检查配置文件
+  %s validate -config configs/config.yaml
+
 配置文件示例请参考 configs/config.yaml
 
 更多信息请访问: https://github.com/scodemay/lightweight-descheduler
-`, appName, version, appName, appName, appName, appName)
+`, appName, version, appName, appName, appName, appName, appName, appName, appName, appName)
 }
@@ -1,11 +1,18 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"lightweight-descheduler/pkg/schedule"
 )
 
 // Config 重调度器的主要配置
@@ -13,15 +20,33 @@ type Config struct {
 	// Interval 重调度运行间隔
 	Interval time.Duration `yaml:"interval"`
 
+	// Mode 运行模式 (continuous, once)，默认为continuous
+	Mode string `yaml:"mode,omitempty"`
+
 	// DryRun 是否只是模拟运行，不实际驱逐Pod
 	DryRun bool `yaml:"dryRun"`
 
+	// DryRunReport DryRun模式下每次循环结束时输出的驱逐计划报告
+	DryRunReport *DryRunReportConfig `yaml:"dryRunReport,omitempty"`
+
 	// NodeSelector 用于选择要处理的节点
 	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
 
+	// Schedule 全局驱逐时间安排，时间安排之外策略只观察不驱逐
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
+	// Triggers 事件触发配置，在固定间隔之外由集群变化触发重调度循环
+	Triggers *TriggersConfig `yaml:"triggers,omitempty"`
+
 	// Limits 驱逐限制配置
 	Limits EvictionLimits `yaml:"limits"`
 
+	// EvictionPolicy 可驱逐性规则，决定哪些Pod可以被驱逐
+	EvictionPolicy *EvictionPolicyConfig `yaml:"evictionPolicy,omitempty"`
+
+	// Audit 驱逐决策审计日志配置
+	Audit *AuditConfig `yaml:"audit,omitempty"`
+
 	// Strategies 启用的策略配置
 	Strategies StrategiesConfig `yaml:"strategies"`
 
@@ -41,6 +66,115 @@ type EvictionLimits struct {
 	MaxPodsToEvictTotal int `yaml:"maxPodsToEvictTotal"`
 }
 
+// EvictionPolicyConfig 可驱逐性规则配置，未配置时保持保守的默认规则
+// DaemonSet Pod、静态Pod和正在删除的Pod始终不驱逐
+type EvictionPolicyConfig struct {
+	// EvictLocalStoragePods 是否驱逐使用本地存储 (hostPath, emptyDir) 的Pod，默认为false
+	EvictLocalStoragePods bool `yaml:"evictLocalStoragePods"`
+
+	// EvictBoundedEmptyDirPods EvictLocalStoragePods为false时，是否驱逐只使用有界emptyDir的Pod，
+	// 即每个emptyDir都设置了sizeLimit或使用内存介质，且没有hostPath。默认为false
+	EvictBoundedEmptyDirPods bool `yaml:"evictBoundedEmptyDirPods"`
+
+	// EvictSystemCriticalPods 是否驱逐使用system-cluster-critical或system-node-critical优先级类的Pod，默认为false
+	EvictSystemCriticalPods bool `yaml:"evictSystemCriticalPods"`
+
+	// EvictBarePods 是否驱逐没有控制器且未失败的Pod，这类Pod驱逐后不会重建，默认为false
+	EvictBarePods bool `yaml:"evictBarePods"`
+
+	// ProtectedNamespaces 不驱逐的命名空间，未配置时为 kube-system, kube-public, kube-node-lease，配置为 [] 时不保护任何命名空间
+	ProtectedNamespaces []string `yaml:"protectedNamespaces"`
+
+	// PriorityThreshold 优先级不低于此值的Pod不驱逐，未配置时不限制
+	PriorityThreshold *int32 `yaml:"priorityThreshold,omitempty"`
+}
+
+// DefaultProtectedNamespaces 默认不驱逐的命名空间
+var DefaultProtectedNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// DryRunReportConfig 驱逐计划报告配置
+type DryRunReportConfig struct {
+	// Format 报告格式 (table, json, yaml)，默认为table
+	Format string `yaml:"format,omitempty"`
+
+	// Path 报告输出文件路径，每次循环覆盖，为空时输出到标准输出
+	Path string `yaml:"path,omitempty"`
+}
+
+// 运行模式
+const (
+	// ModeContinuous 按Interval定期运行，直到进程退出
+	ModeContinuous = "continuous"
+
+	// ModeOnce 只运行一次循环后退出，用于CronJob部署
+	ModeOnce = "once"
+)
+
+// 驱逐计划报告格式
+const (
+	ReportFormatTable = "table"
+	ReportFormatJSON  = "json"
+	ReportFormatYAML  = "yaml"
+)
+
+// AuditConfig 驱逐决策审计日志配置，每个候选Pod的决策输出一行JSON记录
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Path 审计日志文件路径，为空时输出到标准输出
+	Path string `yaml:"path,omitempty"`
+
+	// MaxSizeMB 单个审计日志文件的最大大小（MB），超过后轮转，未配置时为100，为0时不轮转
+	MaxSizeMB *int `yaml:"maxSizeMB,omitempty"`
+
+	// MaxBackups 保留的轮转文件数量，未配置时为3，为0时轮转时直接删除旧文件
+	MaxBackups *int `yaml:"maxBackups,omitempty"`
+}
+
+// ScheduleConfig 驱逐时间安排配置，cron和windows二选一
+type ScheduleConfig struct {
+	// Timezone IANA时区名称，如 Asia/Shanghai，默认UTC
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// Cron 5字段cron表达式，循环开始时间所在的分钟匹配时允许驱逐，如 "* 0-6 * * *" 表示每天0点到7点；
+	// 匹配的分钟互不相邻的表达式（如 "0 2 * * *"）表示触发点，从上次循环到本次循环之间有任一分钟匹配时允许驱逐
+	Cron string `yaml:"cron,omitempty"`
+
+	// Windows 允许驱逐的时间窗口列表
+	Windows []schedule.Window `yaml:"windows,omitempty"`
+}
+
+// Parse 解析驱逐时间安排
+func (c *ScheduleConfig) Parse() (*schedule.Schedule, error) {
+	return schedule.Parse(c.Timezone, c.Cron, c.Windows)
+}
+
+// TriggersConfig 事件触发配置
+type TriggersConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// NodeAdded 新节点加入集群时触发
+	NodeAdded bool `yaml:"nodeAdded"`
+
+	// NodeReady 节点从NotReady变为Ready时触发
+	NodeReady bool `yaml:"nodeReady"`
+
+	// NodeUncordoned 节点解除cordon（重新可调度）时触发
+	NodeUncordoned bool `yaml:"nodeUncordoned"`
+
+	// PodFailureThreshold podFailureWindow内失败的Pod数量达到此值时触发，0表示不启用
+	PodFailureThreshold int `yaml:"podFailureThreshold"`
+
+	// PodFailureWindow 统计Pod失败数量的时间窗口
+	PodFailureWindow time.Duration `yaml:"podFailureWindow"`
+
+	// DebouncePeriod 收到事件后等待的时间，期间的其他事件合并为一次循环
+	DebouncePeriod time.Duration `yaml:"debouncePeriod"`
+
+	// MinInterval 两次循环之间的最小间隔，防止频繁事件导致循环过于频繁
+	MinInterval time.Duration `yaml:"minInterval"`
+}
+
 // StrategiesConfig 策略配置
 type StrategiesConfig struct {
 	// RemoveFailedPods 失败Pod清理策略
@@ -49,14 +183,38 @@ type StrategiesConfig struct {
 	// LowNodeUtilization 低节点利用率策略
 	LowNodeUtilization *LowNodeUtilizationConfig `yaml:"lowNodeUtilization,omitempty"`
 
+	// HighNodeUtilization 高节点利用率（装箱）策略
+	HighNodeUtilization *HighNodeUtilizationConfig `yaml:"highNodeUtilization,omitempty"`
+
 	// RemoveDuplicates 重复Pod清理策略
 	RemoveDuplicates *RemoveDuplicatesConfig `yaml:"removeDuplicates,omitempty"`
+
+	// RemovePodsViolatingNodeAffinity 违反节点亲和性的Pod清理策略
+	RemovePodsViolatingNodeAffinity *RemovePodsViolatingNodeAffinityConfig `yaml:"removePodsViolatingNodeAffinity,omitempty"`
+
+	// RemovePodsViolatingNodeTaints 不容忍节点污点的Pod清理策略
+	RemovePodsViolatingNodeTaints *RemovePodsViolatingNodeTaintsConfig `yaml:"removePodsViolatingNodeTaints,omitempty"`
+
+	// RemovePodsViolatingTopologySpreadConstraints 违反拓扑分布约束的Pod清理策略
+	RemovePodsViolatingTopologySpreadConstraints *RemovePodsViolatingTopologySpreadConstraintsConfig `yaml:"removePodsViolatingTopologySpreadConstraints,omitempty"`
+
+	// RemovePodsViolatingInterPodAntiAffinity 违反Pod间反亲和性的Pod清理策略
+	RemovePodsViolatingInterPodAntiAffinity *RemovePodsViolatingInterPodAntiAffinityConfig `yaml:"removePodsViolatingInterPodAntiAffinity,omitempty"`
+
+	// PodLifeTime 长时间运行Pod回收策略
+	PodLifeTime *PodLifeTimeConfig `yaml:"podLifeTime,omitempty"`
+
+	// RemovePodsHavingTooManyRestarts 重启次数过多的Pod清理策略
+	RemovePodsHavingTooManyRestarts *RemovePodsHavingTooManyRestartsConfig `yaml:"removePodsHavingTooManyRestarts,omitempty"`
 }
 
 // RemoveFailedPodsConfig 失败Pod清理策略配置
 type RemoveFailedPodsConfig struct {
 	Enabled bool `yaml:"enabled"`
 
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
 	// MinPodLifetimeSeconds Pod最小存活时间（秒），小于此时间的Pod不会被驱逐
 	MinPodLifetimeSeconds int `yaml:"minPodLifetimeSeconds"`
 
@@ -74,6 +232,9 @@ type RemoveFailedPodsConfig struct {
 type LowNodeUtilizationConfig struct {
 	Enabled bool `yaml:"enabled"`
 
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
 	// Thresholds 节点利用率阈值，低于此值的节点被认为是低利用率节点
 	Thresholds ResourceThresholds `yaml:"thresholds"`
 
@@ -82,12 +243,81 @@ type LowNodeUtilizationConfig struct {
 
 	// NumberOfNodes 只有当低利用率节点数量大于此值时才运行此策略
 	NumberOfNodes int `yaml:"numberOfNodes"`
+
+	// UtilizationSource 利用率数据来源 (requests, metrics)，默认为requests
+	UtilizationSource string `yaml:"utilizationSource,omitempty"`
+}
+
+// HighNodeUtilizationConfig 高节点利用率（装箱）策略配置
+type HighNodeUtilizationConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
+	// Thresholds 节点利用率阈值，所有资源都低于此值的节点会被清空
+	Thresholds ResourceThresholds `yaml:"thresholds"`
+
+	// MaxNodesToEmptyPerCycle 每次运行最多清空的节点数量
+	MaxNodesToEmptyPerCycle int `yaml:"maxNodesToEmptyPerCycle"`
 }
 
+// 利用率数据来源
+const (
+	// UtilizationSourceRequests 基于Pod资源请求计算利用率
+	UtilizationSourceRequests = "requests"
+
+	// UtilizationSourceMetrics 基于metrics-server上报的实际使用量计算利用率
+	UtilizationSourceMetrics = "metrics"
+)
+
 // RemoveDuplicatesConfig 重复Pod清理策略配置
 type RemoveDuplicatesConfig struct {
 	Enabled bool `yaml:"enabled"`
 
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
+	// ExcludeOwnerKinds 排除的Owner类型
+	ExcludeOwnerKinds []string `yaml:"excludeOwnerKinds,omitempty"`
+
+	// IncludedNamespaces 只处理这些命名空间的Pod
+	IncludedNamespaces []string `yaml:"includedNamespaces,omitempty"`
+
+	// ExcludedNamespaces 排除这些命名空间的Pod
+	ExcludedNamespaces []string `yaml:"excludedNamespaces,omitempty"`
+}
+
+// RemovePodsViolatingNodeAffinityConfig 违反节点亲和性的Pod清理策略配置
+type RemovePodsViolatingNodeAffinityConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
+	// ExcludeOwnerKinds 排除的Owner类型
+	ExcludeOwnerKinds []string `yaml:"excludeOwnerKinds,omitempty"`
+
+	// IncludedNamespaces 只处理这些命名空间的Pod
+	IncludedNamespaces []string `yaml:"includedNamespaces,omitempty"`
+
+	// ExcludedNamespaces 排除这些命名空间的Pod
+	ExcludedNamespaces []string `yaml:"excludedNamespaces,omitempty"`
+}
+
+// RemovePodsViolatingNodeTaintsConfig 不容忍节点污点的Pod清理策略配置
+type RemovePodsViolatingNodeTaintsConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
+	// IncludePreferNoSchedule 是否同时考虑PreferNoSchedule污点
+	IncludePreferNoSchedule bool `yaml:"includePreferNoSchedule"`
+
+	// IncludedTaintKeys 只处理这些键的污点，为空时处理所有污点
+	IncludedTaintKeys []string `yaml:"includedTaintKeys,omitempty"`
+
 	// ExcludeOwnerKinds 排除的Owner类型
 	ExcludeOwnerKinds []string `yaml:"excludeOwnerKinds,omitempty"`
 
@@ -98,19 +328,108 @@ type RemoveDuplicatesConfig struct {
 	ExcludedNamespaces []string `yaml:"excludedNamespaces,omitempty"`
 }
 
-// ResourceThresholds 资源阈值配置
-type ResourceThresholds struct {
-	// CPU CPU利用率阈值 (百分比, 0-100)
-	CPU int `yaml:"cpu"`
+// RemovePodsViolatingTopologySpreadConstraintsConfig 违反拓扑分布约束的Pod清理策略配置
+type RemovePodsViolatingTopologySpreadConstraintsConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
+	// IncludeSoftConstraints 是否同时处理whenUnsatisfiable为ScheduleAnyway的软约束
+	IncludeSoftConstraints bool `yaml:"includeSoftConstraints"`
+
+	// IncludedNamespaces 只处理这些命名空间的Pod
+	IncludedNamespaces []string `yaml:"includedNamespaces,omitempty"`
+
+	// ExcludedNamespaces 排除这些命名空间的Pod
+	ExcludedNamespaces []string `yaml:"excludedNamespaces,omitempty"`
+}
+
+// RemovePodsViolatingInterPodAntiAffinityConfig 违反Pod间反亲和性的Pod清理策略配置
+type RemovePodsViolatingInterPodAntiAffinityConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
 
-	// Memory 内存利用率阈值 (百分比, 0-100)
-	Memory int `yaml:"memory"`
+	// IncludedNamespaces 只驱逐这些命名空间的Pod
+	IncludedNamespaces []string `yaml:"includedNamespaces,omitempty"`
 
-	// Pods Pod数量利用率阈值 (百分比, 0-100)
-	Pods int `yaml:"pods"`
+	// ExcludedNamespaces 不驱逐这些命名空间的Pod
+	ExcludedNamespaces []string `yaml:"excludedNamespaces,omitempty"`
+}
+
+// PodLifeTimeConfig 长时间运行Pod回收策略配置
+type PodLifeTimeConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
+	// MaxPodLifeTimeSeconds Pod最大存活时间（秒），超过此时间的Pod会被驱逐
+	MaxPodLifeTimeSeconds int `yaml:"maxPodLifeTimeSeconds"`
+
+	// PodStatuses 只处理这些状态的Pod，可以是Pod phase（如Running、Pending）
+	// 或容器等待原因（如CrashLoopBackOff），为空时处理所有状态
+	PodStatuses []string `yaml:"podStatuses,omitempty"`
+
+	// LabelSelector 只处理匹配此标签选择器的Pod，格式同kubectl -l，如 "app=web,tier!=db"
+	LabelSelector string `yaml:"labelSelector,omitempty"`
+
+	// IncludedNamespaces 只处理这些命名空间的Pod
+	IncludedNamespaces []string `yaml:"includedNamespaces,omitempty"`
+
+	// ExcludedNamespaces 排除这些命名空间的Pod
+	ExcludedNamespaces []string `yaml:"excludedNamespaces,omitempty"`
+}
+
+// RemovePodsHavingTooManyRestartsConfig 重启次数过多的Pod清理策略配置
+type RemovePodsHavingTooManyRestartsConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Schedule 此策略的驱逐时间安排，覆盖全局schedule
+	Schedule *ScheduleConfig `yaml:"schedule,omitempty"`
+
+	// PodRestartThreshold 容器重启次数之和达到此值的Pod会被驱逐
+	PodRestartThreshold int `yaml:"podRestartThreshold"`
+
+	// IncludingInitContainers 是否把init容器的重启次数计算在内
+	IncludingInitContainers bool `yaml:"includingInitContainers"`
+
+	// SkipIfRestartingOnOtherNodes 同一控制器在其他节点上的Pod也达到阈值时不驱逐，
+	// 用于区分节点问题和应用本身的问题。没有控制器的Pod或同一控制器的Pod都在当前节点上时无法判断，照常驱逐
+	SkipIfRestartingOnOtherNodes bool `yaml:"skipIfRestartingOnOtherNodes"`
+
+	// ExcludeOwnerKinds 排除的Owner类型
+	ExcludeOwnerKinds []string `yaml:"excludeOwnerKinds,omitempty"`
+
+	// IncludedNamespaces 只处理这些命名空间的Pod
+	IncludedNamespaces []string `yaml:"includedNamespaces,omitempty"`
+
+	// ExcludedNamespaces 排除这些命名空间的Pod
+	ExcludedNamespaces []string `yaml:"excludedNamespaces,omitempty"`
+}
+
+// ResourceThresholds 资源阈值配置，按资源名称（百分比, 0-100）索引
+// 除cpu、memory、pods外，也可以使用ephemeral-storage、hugepages-2Mi、nvidia.com/gpu等任意资源名称，
+// 未配置的其他资源不参与利用率判断；cpu、memory、pods与旧版本一致，未配置时按0处理
+type ResourceThresholds map[string]int
+
+// ValidationError 配置验证错误，包含发现的所有问题
+type ValidationError struct {
+	Problems []string
+}
+
+// Error 实现error接口
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0]
+	}
+	return fmt.Sprintf("%d problems: %s", len(e.Problems), strings.Join(e.Problems, "; "))
 }
 
 // LoadConfig 从文件加载配置
+// 未知字段（如拼写错误的字段名）视为错误。字段类型错误、未知字段和语义问题会一起通过*ValidationError返回
 func LoadConfig(filepath string) (*Config, error) {
 	data, err := os.ReadFile(filepath)
 	if err != nil {
@@ -118,8 +437,17 @@ func LoadConfig(filepath string) (*Config, error) {
 	}
 
 	config := &Config{}
-	if err := yaml.Unmarshal(data, config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %v", err)
+	var problems []string
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		// 类型错误和未知字段不会中断解析，与后续的验证问题一起报告；语法错误无法继续
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			return nil, fmt.Errorf("failed to parse config file: %v", err)
+		}
+		problems = append(problems, typeErr.Errors...)
 	}
 
 	// 设置默认值
@@ -128,8 +456,9 @@ func LoadConfig(filepath string) (*Config, error) {
 	}
 
 	// 验证配置
-	if err := validateConfig(config); err != nil {
-		return nil, fmt.Errorf("invalid config: %v", err)
+	problems = append(problems, validateConfig(config)...)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid config: %w", &ValidationError{Problems: problems})
 	}
 
 	return config, nil
@@ -141,10 +470,51 @@ func setDefaults(config *Config) error {
 		config.Interval = 5 * time.Minute
 	}
 
+	if config.Mode == "" {
+		config.Mode = ModeContinuous
+	}
+
 	if config.LogLevel == "" {
 		config.LogLevel = "info"
 	}
 
+	if config.Triggers != nil {
+		if config.Triggers.PodFailureWindow == 0 {
+			config.Triggers.PodFailureWindow = 5 * time.Minute
+		}
+		if config.Triggers.DebouncePeriod == 0 {
+			config.Triggers.DebouncePeriod = 30 * time.Second
+		}
+		if config.Triggers.MinInterval == 0 {
+			config.Triggers.MinInterval = time.Minute
+		}
+	}
+
+	if config.DryRunReport == nil {
+		config.DryRunReport = &DryRunReportConfig{}
+	}
+	if config.DryRunReport.Format == "" {
+		config.DryRunReport.Format = ReportFormatTable
+	}
+
+	if config.EvictionPolicy == nil {
+		config.EvictionPolicy = &EvictionPolicyConfig{}
+	}
+	if config.EvictionPolicy.ProtectedNamespaces == nil {
+		config.EvictionPolicy.ProtectedNamespaces = append([]string(nil), DefaultProtectedNamespaces...)
+	}
+
+	if config.Audit != nil {
+		if config.Audit.MaxSizeMB == nil {
+			maxSizeMB := 100
+			config.Audit.MaxSizeMB = &maxSizeMB
+		}
+		if config.Audit.MaxBackups == nil {
+			maxBackups := 3
+			config.Audit.MaxBackups = &maxBackups
+		}
+	}
+
 	if config.Limits.MaxPodsToEvictPerNode == 0 {
 		config.Limits.MaxPodsToEvictPerNode = 10
 	}
@@ -157,50 +527,222 @@ func setDefaults(config *Config) error {
 		config.Limits.MaxPodsToEvictTotal = 50
 	}
 
+	if config.Strategies.HighNodeUtilization != nil && config.Strategies.HighNodeUtilization.MaxNodesToEmptyPerCycle == 0 {
+		config.Strategies.HighNodeUtilization.MaxNodesToEmptyPerCycle = 1
+	}
+
+	if config.Strategies.LowNodeUtilization != nil && config.Strategies.LowNodeUtilization.UtilizationSource == "" {
+		config.Strategies.LowNodeUtilization.UtilizationSource = UtilizationSourceRequests
+	}
+
 	return nil
 }
 
-// validateConfig 验证配置有效性
-func validateConfig(config *Config) error {
-	if config.Interval < time.Minute {
-		return fmt.Errorf("interval must be at least 1 minute")
+// validateConfig 验证配置有效性，返回发现的所有问题
+func validateConfig(config *Config) []string {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch config.Mode {
+	case ModeContinuous:
+		if config.Interval < time.Minute {
+			addf("interval must be at least 1 minute")
+		}
+	case ModeOnce:
+		// 单次运行时不使用运行间隔
+	default:
+		addf("invalid mode %q: must be %q or %q", config.Mode, ModeContinuous, ModeOnce)
+	}
+
+	problems = append(problems, validateSchedules(config)...)
+
+	if config.Triggers != nil && config.Triggers.Enabled {
+		if config.Triggers.PodFailureThreshold < 0 {
+			addf("triggers.podFailureThreshold must be >= 0")
+		}
+		if config.Triggers.PodFailureWindow <= 0 {
+			addf("triggers.podFailureWindow must be > 0")
+		}
+		if config.Triggers.DebouncePeriod < 0 {
+			addf("triggers.debouncePeriod must be >= 0")
+		}
+		if config.Triggers.MinInterval < 10*time.Second {
+			addf("triggers.minInterval must be at least 10 seconds")
+		}
+	}
+
+	switch config.DryRunReport.Format {
+	case ReportFormatTable, ReportFormatJSON, ReportFormatYAML:
+	default:
+		addf("invalid dryRunReport.format %q: must be %q, %q or %q",
+			config.DryRunReport.Format, ReportFormatTable, ReportFormatJSON, ReportFormatYAML)
+	}
+
+	if config.Audit != nil && config.Audit.Enabled {
+		if config.Audit.MaxSizeMB != nil && *config.Audit.MaxSizeMB < 0 {
+			addf("audit.maxSizeMB must be >= 0")
+		}
+		if config.Audit.MaxBackups != nil && *config.Audit.MaxBackups < 0 {
+			addf("audit.maxBackups must be >= 0")
+		}
 	}
 
 	if config.Limits.MaxPodsToEvictPerNode < 0 {
-		return fmt.Errorf("maxPodsToEvictPerNode must be >= 0")
+		addf("limits.maxPodsToEvictPerNode must be >= 0")
 	}
 
 	if config.Limits.MaxPodsToEvictPerNamespace < 0 {
-		return fmt.Errorf("maxPodsToEvictPerNamespace must be >= 0")
+		addf("limits.maxPodsToEvictPerNamespace must be >= 0")
 	}
 
 	if config.Limits.MaxPodsToEvictTotal < 0 {
-		return fmt.Errorf("maxPodsToEvictTotal must be >= 0")
+		addf("limits.maxPodsToEvictTotal must be >= 0")
+	}
+
+	for i, namespace := range config.EvictionPolicy.ProtectedNamespaces {
+		if namespace == "" {
+			addf("evictionPolicy.protectedNamespaces[%d] must not be empty", i)
+		}
 	}
 
 	// 验证策略配置
-	if config.Strategies.LowNodeUtilization != nil && config.Strategies.LowNodeUtilization.Enabled {
-		if err := validateResourceThresholds(&config.Strategies.LowNodeUtilization.Thresholds); err != nil {
-			return fmt.Errorf("invalid thresholds: %v", err)
+	if lowNode := config.Strategies.LowNodeUtilization; lowNode != nil && lowNode.Enabled {
+		problems = append(problems, validateResourceThresholds("lowNodeUtilization.thresholds", lowNode.Thresholds)...)
+		problems = append(problems, validateResourceThresholds("lowNodeUtilization.targetThresholds", lowNode.TargetThresholds)...)
+
+		// 低利用率阈值必须低于目标阈值，否则同一个节点可能既是驱逐来源又是目标，Pod会来回迁移
+		for _, name := range sortedThresholdNames(lowNode.Thresholds) {
+			target, ok := lowNode.TargetThresholds[name]
+			if !ok {
+				addf("lowNodeUtilization: resource %q is set in thresholds but not in targetThresholds", name)
+				continue
+			}
+			if lowNode.Thresholds[name] >= target {
+				addf("lowNodeUtilization: thresholds.%s (%d) must be below targetThresholds.%s (%d)",
+					name, lowNode.Thresholds[name], name, target)
+			}
 		}
-		if err := validateResourceThresholds(&config.Strategies.LowNodeUtilization.TargetThresholds); err != nil {
-			return fmt.Errorf("invalid targetThresholds: %v", err)
+		for _, name := range sortedThresholdNames(lowNode.TargetThresholds) {
+			if _, ok := lowNode.Thresholds[name]; !ok {
+				addf("lowNodeUtilization: resource %q is set in targetThresholds but not in thresholds", name)
+			}
+		}
+
+		switch lowNode.UtilizationSource {
+		case UtilizationSourceRequests, UtilizationSourceMetrics:
+		default:
+			addf("invalid lowNodeUtilization.utilizationSource %q: must be %q or %q",
+				lowNode.UtilizationSource, UtilizationSourceRequests, UtilizationSourceMetrics)
 		}
 	}
 
-	return nil
+	if highNode := config.Strategies.HighNodeUtilization; highNode != nil && highNode.Enabled {
+		problems = append(problems, validateResourceThresholds("highNodeUtilization.thresholds", highNode.Thresholds)...)
+		if highNode.MaxNodesToEmptyPerCycle < 0 {
+			addf("highNodeUtilization.maxNodesToEmptyPerCycle must be >= 0")
+		}
+	}
+
+	if config.Strategies.RemovePodsHavingTooManyRestarts != nil && config.Strategies.RemovePodsHavingTooManyRestarts.Enabled {
+		if config.Strategies.RemovePodsHavingTooManyRestarts.PodRestartThreshold <= 0 {
+			addf("removePodsHavingTooManyRestarts.podRestartThreshold must be > 0")
+		}
+	}
+
+	if config.Strategies.PodLifeTime != nil && config.Strategies.PodLifeTime.Enabled {
+		if config.Strategies.PodLifeTime.MaxPodLifeTimeSeconds <= 0 {
+			addf("podLifeTime.maxPodLifeTimeSeconds must be > 0")
+		}
+		if _, err := labels.Parse(config.Strategies.PodLifeTime.LabelSelector); err != nil {
+			addf("invalid podLifeTime.labelSelector: %v", err)
+		}
+	}
+
+	return problems
 }
 
-// validateResourceThresholds 验证资源阈值配置
-func validateResourceThresholds(thresholds *ResourceThresholds) error {
-	if thresholds.CPU < 0 || thresholds.CPU > 100 {
-		return fmt.Errorf("CPU threshold must be between 0 and 100")
+// validateSchedules 验证全局和各策略的驱逐时间安排
+func validateSchedules(config *Config) []string {
+	schedules := map[string]*ScheduleConfig{"schedule": config.Schedule}
+
+	strategies := config.Strategies
+	if strategies.RemoveFailedPods != nil {
+		schedules["removeFailedPods.schedule"] = strategies.RemoveFailedPods.Schedule
 	}
-	if thresholds.Memory < 0 || thresholds.Memory > 100 {
-		return fmt.Errorf("Memory threshold must be between 0 and 100")
+	if strategies.LowNodeUtilization != nil {
+		schedules["lowNodeUtilization.schedule"] = strategies.LowNodeUtilization.Schedule
 	}
-	if thresholds.Pods < 0 || thresholds.Pods > 100 {
-		return fmt.Errorf("Pods threshold must be between 0 and 100")
+	if strategies.HighNodeUtilization != nil {
+		schedules["highNodeUtilization.schedule"] = strategies.HighNodeUtilization.Schedule
 	}
-	return nil
+	if strategies.RemoveDuplicates != nil {
+		schedules["removeDuplicates.schedule"] = strategies.RemoveDuplicates.Schedule
+	}
+	if strategies.RemovePodsViolatingNodeAffinity != nil {
+		schedules["removePodsViolatingNodeAffinity.schedule"] = strategies.RemovePodsViolatingNodeAffinity.Schedule
+	}
+	if strategies.RemovePodsViolatingNodeTaints != nil {
+		schedules["removePodsViolatingNodeTaints.schedule"] = strategies.RemovePodsViolatingNodeTaints.Schedule
+	}
+	if strategies.RemovePodsViolatingTopologySpreadConstraints != nil {
+		schedules["removePodsViolatingTopologySpreadConstraints.schedule"] = strategies.RemovePodsViolatingTopologySpreadConstraints.Schedule
+	}
+	if strategies.RemovePodsViolatingInterPodAntiAffinity != nil {
+		schedules["removePodsViolatingInterPodAntiAffinity.schedule"] = strategies.RemovePodsViolatingInterPodAntiAffinity.Schedule
+	}
+	if strategies.PodLifeTime != nil {
+		schedules["podLifeTime.schedule"] = strategies.PodLifeTime.Schedule
+	}
+	if strategies.RemovePodsHavingTooManyRestarts != nil {
+		schedules["removePodsHavingTooManyRestarts.schedule"] = strategies.RemovePodsHavingTooManyRestarts.Schedule
+	}
+
+	names := make([]string, 0, len(schedules))
+	for name := range schedules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		if schedules[name] == nil {
+			continue
+		}
+		if _, err := schedules[name].Parse(); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid %s: %v", name, err))
+		}
+	}
+	return problems
+}
+
+// validateResourceThresholds 验证资源阈值配置，field为问题描述中使用的字段名
+func validateResourceThresholds(field string, thresholds ResourceThresholds) []string {
+	// 没有任何阈值时所有节点都会被当作低利用率节点
+	if len(thresholds) == 0 {
+		return []string{fmt.Sprintf("%s: at least one resource threshold must be set", field)}
+	}
+
+	var problems []string
+	for _, name := range sortedThresholdNames(thresholds) {
+		if name == "" {
+			problems = append(problems, fmt.Sprintf("%s: resource name must not be empty", field))
+			continue
+		}
+		if value := thresholds[name]; value < 0 || value > 100 {
+			problems = append(problems, fmt.Sprintf("%s.%s must be between 0 and 100", field, name))
+		}
+	}
+	return problems
+}
+
+// sortedThresholdNames 返回排序后的资源名称，保证问题按固定顺序报告
+func sortedThresholdNames(thresholds ResourceThresholds) []string {
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,68 @@
+package eviction
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Pod和命名空间上控制驱逐的注解，命名空间上的注解作为其中所有Pod的默认值，Pod上的注解优先
+const (
+	// EvictAnnotationKey 为"false"时不驱逐Pod；为"true"时允许驱逐，即使evictionPolicy规则（如本地存储）会阻止
+	// DaemonSet Pod、静态Pod、PodDisruptionBudget和驱逐限制不受此注解影响
+	EvictAnnotationKey = "lightweight-descheduler/evict"
+
+	// AllowedStrategiesAnnotationKey 允许驱逐Pod的策略名称，以逗号分隔，如 "PodLifeTime,RemoveDuplicates"
+	AllowedStrategiesAnnotationKey = "lightweight-descheduler/allowed-strategies"
+)
+
+// checkEvictionAnnotations 按Pod和命名空间注解检查strategy是否可以驱逐Pod，forced表示注解强制允许驱逐，需持有e.mu
+func (e *DefaultPodEvictor) checkEvictionAnnotations(pod *v1.Pod, strategy string) (ok, forced bool, reason string) {
+	if value, source, found := e.evictionAnnotation(pod, AllowedStrategiesAnnotationKey); found && !strategyAllowed(value, strategy) {
+		return false, false, fmt.Sprintf("strategy %s is not allowed by %s annotation %s=%q",
+			strategy, source, AllowedStrategiesAnnotationKey, value)
+	}
+
+	value, source, found := e.evictionAnnotation(pod, EvictAnnotationKey)
+	if !found {
+		return true, false, ""
+	}
+	evict, err := strconv.ParseBool(value)
+	if err != nil {
+		// 无法识别的值按禁止驱逐处理，以免拼写错误导致Pod被意外驱逐
+		return false, false, fmt.Sprintf("invalid value %q for %s annotation %s", value, source, EvictAnnotationKey)
+	}
+	if !evict {
+		return false, false, fmt.Sprintf("eviction disabled by %s annotation %s=%q", source, EvictAnnotationKey, value)
+	}
+	return true, true, ""
+}
+
+// evictionAnnotation 返回Pod上的注解值，Pod没有设置时使用所在命名空间的注解，source为"pod"或"namespace"，需持有e.mu
+func (e *DefaultPodEvictor) evictionAnnotation(pod *v1.Pod, key string) (value, source string, found bool) {
+	if value, ok := pod.Annotations[key]; ok {
+		return value, "pod", true
+	}
+
+	if e.snapshot == nil {
+		return "", "", false
+	}
+	if namespace := e.snapshot.Namespace(pod.Namespace); namespace != nil {
+		if value, ok := namespace.Annotations[key]; ok {
+			return value, "namespace", true
+		}
+	}
+	return "", "", false
+}
+
+// strategyAllowed 检查策略是否在逗号分隔的策略列表中，策略名称不区分大小写
+func strategyAllowed(strategies, strategy string) bool {
+	for _, name := range strings.Split(strategies, ",") {
+		if strings.EqualFold(strings.TrimSpace(name), strategy) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package eviction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// AuditAction 审计记录中的驱逐决策
+type AuditAction string
+
+const (
+	// AuditActionEvicted Pod已被驱逐
+	AuditActionEvicted AuditAction = "evicted"
+
+	// AuditActionDryRun DryRun或只观察模式下Pod本应被驱逐
+	AuditActionDryRun AuditAction = "dry-run"
+
+	// AuditActionSkipped Pod被策略选中，但因驱逐规则、PDB等原因没有驱逐
+	AuditActionSkipped AuditAction = "skipped"
+
+	// AuditActionLimitReached 达到驱逐限制，Pod没有驱逐
+	AuditActionLimitReached AuditAction = "limit-reached"
+
+	// AuditActionFailed 驱逐请求失败
+	AuditActionFailed AuditAction = "failed"
+)
+
+// AuditRecord 单个候选Pod的驱逐决策记录
+type AuditRecord struct {
+	Timestamp time.Time   `json:"timestamp"`
+	CycleID   string      `json:"cycleId"`
+	Strategy  string      `json:"strategy"`
+	Pod       string      `json:"pod"`
+	Namespace string      `json:"namespace"`
+	Node      string      `json:"node"`
+	Owner     string      `json:"owner,omitempty"`
+	Action    AuditAction `json:"action"`
+	Reason    string      `json:"reason"`
+
+	// NodeUtilization 本次循环开始时Pod所在节点基于资源请求的利用率百分比
+	NodeUtilization map[v1.ResourceName]int `json:"nodeUtilization,omitempty"`
+}
+
+// AuditSink 审计记录的输出目标
+type AuditSink interface {
+	// Write 写入一条审计记录
+	Write(record *AuditRecord) error
+
+	// Close 关闭输出目标
+	Close() error
+}
+
+// NewAuditSink 根据配置创建审计记录输出目标，未配置路径时输出到标准输出
+func NewAuditSink(cfg *config.AuditConfig) (AuditSink, error) {
+	if cfg.Path == "" {
+		return &jsonAuditSink{writer: os.Stdout}, nil
+	}
+
+	var maxSize int64
+	if cfg.MaxSizeMB != nil {
+		maxSize = int64(*cfg.MaxSizeMB) * 1024 * 1024
+	}
+	maxBackups := 0
+	if cfg.MaxBackups != nil {
+		maxBackups = *cfg.MaxBackups
+	}
+
+	file, err := newRotatingFile(cfg.Path, maxSize, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonAuditSink{writer: file, closer: file}, nil
+}
+
+// jsonAuditSink 每条记录输出一行JSON
+type jsonAuditSink struct {
+	mu     sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+// Write 写入一条审计记录
+func (s *jsonAuditSink) Write(record *AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %v", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.writer.Write(data)
+	return err
+}
+
+// Close 关闭输出目标，标准输出不关闭
+func (s *jsonAuditSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// rotatingFile 按大小轮转的日志文件，轮转后的文件依次命名为 path.1、path.2 ...，序号越大越旧
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+// newRotatingFile 打开（或创建）日志文件，新记录追加到文件末尾
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write 写入数据，写入后会超过最大大小时先轮转
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close 关闭当前文件
+func (f *rotatingFile) Close() error {
+	return f.file.Close()
+}
+
+// open 打开日志文件并获取当前大小
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %v", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log %s: %v", f.path, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate 关闭当前文件，依次重命名旧文件并丢弃超出保留数量的文件，然后打开新文件
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log %s: %v", f.path, err)
+	}
+
+	if f.maxBackups == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove audit log %s: %v", f.path, err)
+		}
+		return f.open()
+	}
+
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(backupName(f.path, i), backupName(f.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log %s: %v", f.path, err)
+		}
+	}
+	if err := os.Rename(f.path, backupName(f.path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate audit log %s: %v", f.path, err)
+	}
+
+	return f.open()
+}
+
+// backupName 返回第index个轮转文件的名称
+func backupName(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
+
+// audit 写入一条审计记录，需持有e.mu
+func (e *DefaultPodEvictor) audit(pod *v1.Pod, strategy string, action AuditAction, reason string) {
+	if e.auditSink == nil {
+		return
+	}
+
+	record := &AuditRecord{
+		Timestamp:       time.Now().UTC(),
+		CycleID:         e.cycleID,
+		Strategy:        strategy,
+		Pod:             pod.Name,
+		Namespace:       pod.Namespace,
+		Node:            pod.Spec.NodeName,
+		Owner:           podOwner(pod),
+		Action:          action,
+		Reason:          reason,
+		NodeUtilization: e.nodeUtilization(pod.Spec.NodeName),
+	}
+	if err := e.auditSink.Write(record); err != nil {
+		klog.Errorf("Failed to write audit record for pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+}
+
+// nodeUtilization 返回节点在本次循环快照中基于资源请求的利用率，结果在循环内缓存，需持有e.mu
+func (e *DefaultPodEvictor) nodeUtilization(nodeName string) map[v1.ResourceName]int {
+	if e.snapshot == nil || nodeName == "" {
+		return nil
+	}
+	if percent, ok := e.utilizations[nodeName]; ok {
+		return percent
+	}
+
+	var percent map[v1.ResourceName]int
+	if node := e.snapshot.Node(nodeName); node != nil {
+		percent = utils.CalculateNodeUtilization(node, e.snapshot.PodsOnNode(nodeName)).Percent
+	}
+	e.utilizations[nodeName] = percent
+	return percent
+}
+
+// podOwner 返回Pod的控制器（没有控制器时为第一个Owner），格式为 Kind/Name
+func podOwner(pod *v1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		if len(pod.OwnerReferences) == 0 {
+			return ""
+		}
+		owner = &pod.OwnerReferences[0]
+	}
+	return fmt.Sprintf("%s/%s", owner.Kind, owner.Name)
+}
@@ -0,0 +1,190 @@
+package eviction
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+// EventComponent 事件来源的组件名称
+const EventComponent = "lightweight-descheduler"
+
+// StrategyAnnotationKey 事件上记录发起驱逐的策略名称的注解
+const StrategyAnnotationKey = "lightweight-descheduler/strategy"
+
+// 记录在Pod上的事件原因
+const (
+	// EventReasonEvicted Pod已被驱逐
+	EventReasonEvicted = "Descheduled"
+
+	// EventReasonDryRun DryRun或只观察模式下Pod本应被驱逐
+	EventReasonDryRun = "DescheduledDryRun"
+
+	// EventReasonRefused 驱逐因驱逐限制或PodDisruptionBudget被拒绝
+	EventReasonRefused = "DeschedulingRefused"
+
+	// EventReasonFailed 驱逐请求失败
+	EventReasonFailed = "DeschedulingFailed"
+)
+
+// eventFlushTimeout 停止事件广播前等待已记录事件处理完成的最长时间
+const eventFlushTimeout = 10 * time.Second
+
+// 写入事件遇到网络错误时的重试次数和间隔，停止时最多等待这么久
+const (
+	eventWriteTries      = 3
+	eventWriteRetryDelay = time.Second
+)
+
+// NewEventRecorder 创建向API Server写入事件的EventRecorder，返回的stop函数用于停止事件广播
+// stop会先等待已记录的事件处理完成（写入、被合并丢弃或放弃写入），单次运行后立即退出时事件不会丢失
+func NewEventRecorder(client kubernetes.Interface) (record.EventRecorder, func()) {
+	writer := &eventWriter{
+		sink:       &typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")},
+		correlator: record.NewEventCorrelatorWithOptions(record.CorrelatorOptions{}),
+	}
+	writer.cond = sync.NewCond(&writer.mu)
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartStructuredLogging(4)
+	broadcaster.StartEventWatcher(writer.write)
+
+	recorder := &countingEventRecorder{
+		EventRecorder: broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: EventComponent}),
+		writer:        writer,
+	}
+	stop := func() {
+		if !writer.wait(eventFlushTimeout) {
+			klog.Warningf("Timed out after %v waiting for events to be written", eventFlushTimeout)
+		}
+		broadcaster.Shutdown()
+	}
+	return recorder, stop
+}
+
+// eventWriter 把广播的事件写入API Server，并统计已记录和已处理完成的事件数量
+// 与record.EventBroadcaster.StartRecordingToSink一样先经过事件关联器合并和限流，
+// 但每个事件无论写入成功、失败还是被丢弃都计为处理完成，停止时只需等待计数追平
+type eventWriter struct {
+	sink       record.EventSink
+	correlator *record.EventCorrelator
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	recorded  int
+	processed int
+}
+
+// add 记录一个新事件
+func (w *eventWriter) add() {
+	w.mu.Lock()
+	w.recorded++
+	w.mu.Unlock()
+}
+
+// write 处理广播的一个事件，在事件广播的goroutine中依次调用
+func (w *eventWriter) write(event *v1.Event) {
+	defer func() {
+		w.mu.Lock()
+		w.processed++
+		w.mu.Unlock()
+		w.cond.Broadcast()
+	}()
+
+	// 可能有多个监听者，修改前先复制
+	eventCopy := *event
+	result, err := w.correlator.EventCorrelate(&eventCopy)
+	if err != nil {
+		klog.V(4).Infof("Failed to correlate event %s: %v", eventCopy.Name, err)
+	}
+	if result.Skip {
+		return
+	}
+
+	for tries := 1; ; tries++ {
+		written, err := w.writeOnce(result.Event, result.Patch)
+		if err == nil {
+			w.correlator.UpdateState(written)
+			return
+		}
+
+		// API Server拒绝的事件重试也不会成功
+		if _, rejected := err.(*apierrors.StatusError); rejected || tries >= eventWriteTries {
+			klog.Warningf("Failed to write event %s for %s/%s: %v",
+				result.Event.Reason, result.Event.InvolvedObject.Namespace, result.Event.InvolvedObject.Name, err)
+			return
+		}
+		time.Sleep(eventWriteRetryDelay)
+	}
+}
+
+// writeOnce 创建事件，合并后的重复事件更新已有事件的计数，已有事件不存在时重新创建
+func (w *eventWriter) writeOnce(event *v1.Event, patch []byte) (*v1.Event, error) {
+	if event.Count > 1 {
+		written, err := w.sink.Patch(event, patch)
+		if !apierrors.IsNotFound(err) {
+			return written, err
+		}
+	}
+
+	event.ResourceVersion = ""
+	return w.sink.Create(event)
+}
+
+// wait 等待所有已记录的事件处理完成，超时返回false
+func (w *eventWriter) wait(timeout time.Duration) bool {
+	timer := time.AfterFunc(timeout, w.cond.Broadcast)
+	defer timer.Stop()
+	deadline := time.Now().Add(timeout)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.processed < w.recorded {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		w.cond.Wait()
+	}
+	return true
+}
+
+// countingEventRecorder 统计记录的事件数量
+type countingEventRecorder struct {
+	record.EventRecorder
+	writer *eventWriter
+}
+
+// Event 记录事件
+func (r *countingEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.writer.add()
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf 记录格式化的事件
+func (r *countingEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.writer.add()
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedEventf 记录带注解的格式化事件
+func (r *countingEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.writer.add()
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// recordEvent 在Pod上记录驱逐相关的事件，策略名称同时写入事件注解，便于事件导出工具按策略筛选
+func (e *DefaultPodEvictor) recordEvent(pod *v1.Pod, eventType, eventReason, strategy, message string) {
+	if e.recorder == nil {
+		return
+	}
+	e.recorder.AnnotatedEventf(pod, map[string]string{StrategyAnnotationKey: strategy},
+		eventType, eventReason, "%s (strategy: %s)", message, strategy)
+}
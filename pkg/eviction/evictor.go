@@ -7,20 +7,43 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/metrics"
+	"lightweight-descheduler/pkg/snapshot"
+	"lightweight-descheduler/pkg/utils"
 )
 
 // PodEvictor Pod驱逐器接口
 type PodEvictor interface {
-	// EvictPod 驱逐指定的Pod
-	EvictPod(ctx context.Context, pod *v1.Pod, reason string) error
+	// EvictPod 驱逐指定的Pod，strategy为发起驱逐的策略名称
+	EvictPod(ctx context.Context, pod *v1.Pod, strategy, reason string) error
 
-	// CanEvictPod 检查是否可以驱逐指定的Pod
-	CanEvictPod(pod *v1.Pod) (bool, string)
+	// CanEvictPod 检查strategy是否可以驱逐指定的Pod
+	CanEvictPod(pod *v1.Pod, strategy string) (bool, string)
+
+	// CanEvictAll 检查strategy驱逐所有指定的Pod是否会超出剩余的驱逐限制或PDB预算
+	CanEvictAll(pods []*v1.Pod, strategy string) (bool, string)
+
+	// BeginCycle 开始新的重调度循环，cycleID和快照用于审计记录
+	BeginCycle(cycleID string, snap *snapshot.Snapshot)
+
+	// DryRunReport 返回本次循环的驱逐计划报告
+	DryRunReport() *DryRunReport
+
+	// RecordSkipped 记录被策略选中但没有驱逐的Pod
+	RecordSkipped(pod *v1.Pod, strategy, reason string)
+
+	// SetObserveOnly 设置策略是否处于只观察模式，只观察的策略的驱逐只记录日志和指标
+	SetObserveOnly(strategy string, observeOnly bool)
+
+	// RefreshPodDisruptionBudgets 重新加载PDB并重置本轮已消耗的中断预算
+	RefreshPodDisruptionBudgets(ctx context.Context) error
 
 	// GetEvictionStats 获取驱逐统计信息
 	GetEvictionStats() EvictionStats
@@ -43,8 +66,14 @@ type EvictionStats struct {
 	// EvictedByReason 按原因统计的驱逐数量
 	EvictedByReason map[string]int
 
+	// EvictedByStrategy 按策略统计的驱逐数量
+	EvictedByStrategy map[string]int
+
 	// FailedEvictions 驱逐失败数量
 	FailedEvictions int
+
+	// RefusedByLimits 因达到驱逐限制没有驱逐的Pod数量
+	RefusedByLimits int
 }
 
 // DefaultPodEvictor 默认Pod驱逐器实现
@@ -54,38 +83,102 @@ type DefaultPodEvictor struct {
 	stats       EvictionStats
 	mu          sync.RWMutex
 	gracePeriod *int64
+
+	// recorder 在Pod上记录驱逐事件，为nil时不记录
+	recorder record.EventRecorder
+
+	// budgets 按命名空间索引的PDB预算，每轮循环开始时刷新
+	budgets map[string][]*disruptionBudget
+
+	// observeOnly 处于只观察模式的策略
+	observeOnly map[string]bool
+
+	// auditSink 驱逐决策审计记录的输出目标，为nil时不记录
+	auditSink AuditSink
+
+	// cycleID 当前循环的标识
+	cycleID string
+
+	// snapshot 当前循环的快照，utilizations缓存从中计算的节点利用率
+	snapshot     *snapshot.Snapshot
+	utilizations map[string]map[v1.ResourceName]int
+
+	// plan 本次循环的模拟驱逐，simulated为扣除这些Pod后的节点利用率
+	plan      []plannedEviction
+	simulated map[string]*utils.NodeResourceUtilization
+
+	// limitsHit 本次循环达到的驱逐限制及因此没有驱逐的Pod数量
+	limitsHit map[limitError]int
 }
 
 // NewDefaultPodEvictor 创建默认Pod驱逐器
-func NewDefaultPodEvictor(client kubernetes.Interface, cfg *config.Config) *DefaultPodEvictor {
+// recorder用于在被驱逐的Pod上记录事件，auditSink用于输出驱逐决策审计记录，两者都可以为nil
+func NewDefaultPodEvictor(client kubernetes.Interface, cfg *config.Config, recorder record.EventRecorder, auditSink AuditSink) *DefaultPodEvictor {
 	gracePeriod := int64(30) // 30秒优雅删除时间
 	return &DefaultPodEvictor{
 		client:      client,
 		config:      cfg,
 		gracePeriod: &gracePeriod,
+		recorder:    recorder,
+		auditSink:   auditSink,
+		observeOnly: make(map[string]bool),
 		stats: EvictionStats{
 			EvictedByNode:      make(map[string]int),
 			EvictedByNamespace: make(map[string]int),
 			EvictedByReason:    make(map[string]int),
+			EvictedByStrategy:  make(map[string]int),
 		},
 	}
 }
 
 // EvictPod 实现Pod驱逐
-func (e *DefaultPodEvictor) EvictPod(ctx context.Context, pod *v1.Pod, reason string) error {
+func (e *DefaultPodEvictor) EvictPod(ctx context.Context, pod *v1.Pod, strategy, reason string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	// 只观察的策略不实际驱逐，也不消耗驱逐限制和PDB预算，以免影响其他策略
+	if e.observeOnly[strategy] {
+		klog.Infof("[ObserveOnly] Would evict pod %s/%s on node %s by %s, reason: %s",
+			pod.Namespace, pod.Name, pod.Spec.NodeName, strategy, reason)
+		metrics.RecordEviction(pod.Spec.NodeName, pod.Namespace, strategy, reason, true)
+		e.recordEvent(pod, v1.EventTypeNormal, EventReasonDryRun, strategy,
+			fmt.Sprintf("Would be evicted outside the strategy's eviction schedule: %s", reason))
+		e.audit(pod, strategy, AuditActionDryRun, reason)
+		e.planEviction(pod, strategy, reason, true)
+		return nil
+	}
+
 	// 检查驱逐限制
 	if err := e.checkEvictionLimits(pod); err != nil {
+		e.recordEvent(pod, v1.EventTypeWarning, EventReasonRefused, strategy,
+			fmt.Sprintf("Eviction refused: %v", err))
+		e.audit(pod, strategy, AuditActionLimitReached, err.Error())
+		e.stats.RefusedByLimits++
+		if limitErr, ok := err.(*limitError); ok && e.limitsHit != nil {
+			e.limitsHit[*limitErr]++
+		}
 		return err
 	}
 
+	// 检查PDB预算
+	if ok, budgetReason := e.checkDisruptionBudget(pod); !ok {
+		e.recordEvent(pod, v1.EventTypeWarning, EventReasonRefused, strategy,
+			fmt.Sprintf("Eviction refused: %s", budgetReason))
+		e.audit(pod, strategy, AuditActionSkipped, budgetReason)
+		return fmt.Errorf("skipping pod %s/%s: %s", pod.Namespace, pod.Name, budgetReason)
+	}
+
 	// 如果是DryRun模式，只记录日志不实际驱逐
 	if e.config.DryRun {
 		klog.Infof("[DryRun] Would evict pod %s/%s on node %s, reason: %s",
 			pod.Namespace, pod.Name, pod.Spec.NodeName, reason)
-		e.updateStats(pod, reason, true)
+		e.updateStats(pod, strategy, reason, true)
+		e.consumeDisruptionBudget(pod)
+		metrics.RecordEviction(pod.Spec.NodeName, pod.Namespace, strategy, reason, true)
+		e.recordEvent(pod, v1.EventTypeNormal, EventReasonDryRun, strategy,
+			fmt.Sprintf("[DryRun] Would be evicted: %s", reason))
+		e.audit(pod, strategy, AuditActionDryRun, reason)
+		e.planEviction(pod, strategy, reason, false)
 		return nil
 	}
 
@@ -106,49 +199,153 @@ func (e *DefaultPodEvictor) EvictPod(ctx context.Context, pod *v1.Pod, reason st
 
 	// 执行驱逐
 	err := e.client.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+	if apierrors.IsTooManyRequests(err) {
+		// PDB状态在本轮循环开始后发生了变化，API拒绝驱逐，不计为失败
+		klog.Infof("Eviction of pod %s/%s blocked by pod disruption budget: %v", pod.Namespace, pod.Name, err)
+		e.recordEvent(pod, v1.EventTypeWarning, EventReasonRefused, strategy,
+			fmt.Sprintf("Eviction refused by the API server: %v", err))
+		e.audit(pod, strategy, AuditActionSkipped, fmt.Sprintf("blocked by pod disruption budget: %v", err))
+		return fmt.Errorf("eviction of pod %s/%s blocked by pod disruption budget: %v", pod.Namespace, pod.Name, err)
+	}
 	if err != nil {
 		e.stats.FailedEvictions++
+		metrics.RecordFailedEviction(pod.Spec.NodeName, pod.Namespace, strategy)
 		klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		e.recordEvent(pod, v1.EventTypeWarning, EventReasonFailed, strategy,
+			fmt.Sprintf("Eviction failed: %v", err))
+		e.audit(pod, strategy, AuditActionFailed, err.Error())
 		return fmt.Errorf("failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
 	}
 
 	klog.Infof("Successfully evicted pod %s/%s on node %s, reason: %s",
 		pod.Namespace, pod.Name, pod.Spec.NodeName, reason)
 
-	e.updateStats(pod, reason, true)
+	e.updateStats(pod, strategy, reason, true)
+	e.consumeDisruptionBudget(pod)
+	metrics.RecordEviction(pod.Spec.NodeName, pod.Namespace, strategy, reason, false)
+	e.recordEvent(pod, v1.EventTypeNormal, EventReasonEvicted, strategy,
+		fmt.Sprintf("Evicted by %s: %s", EventComponent, reason))
+	e.audit(pod, strategy, AuditActionEvicted, reason)
 	return nil
 }
 
-// CanEvictPod 检查是否可以驱逐Pod
-func (e *DefaultPodEvictor) CanEvictPod(pod *v1.Pod) (bool, string) {
-	// 系统关键Pod不能驱逐
-	if isSystemCriticalPod(pod) {
-		return false, "system critical pod"
+// BeginCycle 开始新的重调度循环
+func (e *DefaultPodEvictor) BeginCycle(cycleID string, snap *snapshot.Snapshot) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.cycleID = cycleID
+	e.snapshot = snap
+	e.utilizations = make(map[string]map[v1.ResourceName]int)
+	e.plan = nil
+	e.simulated = make(map[string]*utils.NodeResourceUtilization)
+	e.limitsHit = make(map[limitError]int)
+}
+
+// RecordSkipped 记录被策略选中但没有驱逐的Pod
+func (e *DefaultPodEvictor) RecordSkipped(pod *v1.Pod, strategy, reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.audit(pod, strategy, AuditActionSkipped, reason)
+}
+
+// SetObserveOnly 设置策略是否处于只观察模式
+func (e *DefaultPodEvictor) SetObserveOnly(strategy string, observeOnly bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if observeOnly {
+		e.observeOnly[strategy] = true
+	} else {
+		delete(e.observeOnly, strategy)
 	}
+}
 
+// CanEvictPod 检查strategy是否可以驱逐Pod
+func (e *DefaultPodEvictor) CanEvictPod(pod *v1.Pod, strategy string) (bool, string) {
 	// DaemonSet的Pod不能驱逐
-	if isDaemonSetPod(pod) {
+	if IsDaemonSetPod(pod) {
 		return false, "daemonset pod"
 	}
 
 	// 静态Pod不能驱逐
-	if isStaticPod(pod) {
+	if IsStaticPod(pod) {
 		return false, "static pod"
 	}
 
-	// 没有控制器的Pod不能驱逐（除非是失败状态）
-	if isStandalonePod(pod) && pod.Status.Phase != v1.PodFailed {
-		return false, "standalone pod (not failed)"
-	}
-
 	// 正在删除的Pod不能驱逐
 	if pod.DeletionTimestamp != nil {
 		return false, "pod is being deleted"
 	}
 
-	// 有本地存储的Pod默认不驱逐
-	if hasLocalStorage(pod) {
-		return false, "pod has local storage"
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	// Pod和命名空间注解，强制允许驱逐时跳过可驱逐性规则
+	ok, forced, reason := e.checkEvictionAnnotations(pod, strategy)
+	if !ok {
+		return false, reason
+	}
+
+	// 可配置的可驱逐性规则
+	if !forced {
+		if ok, reason := checkEvictionPolicy(e.config.EvictionPolicy, pod); !ok {
+			return false, reason
+		}
+	}
+
+	// 驱逐会突破PodDisruptionBudget的Pod不驱逐
+	if ok, reason := e.checkDisruptionBudget(pod); !ok {
+		return false, reason
+	}
+
+	return true, ""
+}
+
+// CanEvictAll 检查驱逐所有Pod是否会超出剩余的驱逐限制或本轮剩余的PDB预算
+// 只观察的策略不消耗驱逐限制，只检查PDB预算，使驱逐计划与实际驱逐时一致
+func (e *DefaultPodEvictor) CanEvictAll(pods []*v1.Pod, strategy string) (bool, string) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if ok, reason := e.checkDisruptionBudgets(pods); !ok {
+		return false, reason
+	}
+
+	if e.observeOnly[strategy] {
+		return true, ""
+	}
+
+	limits := e.config.Limits
+	byNode := make(map[string]int)
+	byNamespace := make(map[string]int)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			byNode[pod.Spec.NodeName]++
+		}
+		byNamespace[pod.Namespace]++
+	}
+
+	if limits.MaxPodsToEvictTotal > 0 && e.stats.TotalEvicted+len(pods) > limits.MaxPodsToEvictTotal {
+		return false, fmt.Sprintf("evicting %d pods would exceed total eviction limit: %d (%d already evicted)",
+			len(pods), limits.MaxPodsToEvictTotal, e.stats.TotalEvicted)
+	}
+	if limits.MaxPodsToEvictPerNode > 0 {
+		for node, count := range byNode {
+			if e.stats.EvictedByNode[node]+count > limits.MaxPodsToEvictPerNode {
+				return false, fmt.Sprintf("evicting %d pods would exceed node %s eviction limit: %d (%d already evicted)",
+					count, node, limits.MaxPodsToEvictPerNode, e.stats.EvictedByNode[node])
+			}
+		}
+	}
+	if limits.MaxPodsToEvictPerNamespace > 0 {
+		for namespace, count := range byNamespace {
+			if e.stats.EvictedByNamespace[namespace]+count > limits.MaxPodsToEvictPerNamespace {
+				return false, fmt.Sprintf("evicting %d pods would exceed namespace %s eviction limit: %d (%d already evicted)",
+					count, namespace, limits.MaxPodsToEvictPerNamespace, e.stats.EvictedByNamespace[namespace])
+			}
+		}
 	}
 
 	return true, ""
@@ -163,9 +360,11 @@ func (e *DefaultPodEvictor) GetEvictionStats() EvictionStats {
 	stats := EvictionStats{
 		TotalEvicted:       e.stats.TotalEvicted,
 		FailedEvictions:    e.stats.FailedEvictions,
+		RefusedByLimits:    e.stats.RefusedByLimits,
 		EvictedByNode:      make(map[string]int),
 		EvictedByNamespace: make(map[string]int),
 		EvictedByReason:    make(map[string]int),
+		EvictedByStrategy:  make(map[string]int),
 	}
 
 	for k, v := range e.stats.EvictedByNode {
@@ -177,6 +376,9 @@ func (e *DefaultPodEvictor) GetEvictionStats() EvictionStats {
 	for k, v := range e.stats.EvictedByReason {
 		stats.EvictedByReason[k] = v
 	}
+	for k, v := range e.stats.EvictedByStrategy {
+		stats.EvictedByStrategy[k] = v
+	}
 
 	return stats
 }
@@ -190,39 +392,69 @@ func (e *DefaultPodEvictor) ResetStats() {
 		EvictedByNode:      make(map[string]int),
 		EvictedByNamespace: make(map[string]int),
 		EvictedByReason:    make(map[string]int),
+		EvictedByStrategy:  make(map[string]int),
 	}
 }
 
-// checkEvictionLimits 检查驱逐限制
+// checkEvictionLimits 检查驱逐限制，达到限制时返回*limitError
 func (e *DefaultPodEvictor) checkEvictionLimits(pod *v1.Pod) error {
 	limits := e.config.Limits
 
 	// 检查总驱逐限制
 	if limits.MaxPodsToEvictTotal > 0 && e.stats.TotalEvicted >= limits.MaxPodsToEvictTotal {
-		return fmt.Errorf("reached total eviction limit: %d", limits.MaxPodsToEvictTotal)
+		return &limitError{limit: limitTotal, max: limits.MaxPodsToEvictTotal}
 	}
 
 	// 检查节点驱逐限制
 	if limits.MaxPodsToEvictPerNode > 0 && pod.Spec.NodeName != "" {
 		if e.stats.EvictedByNode[pod.Spec.NodeName] >= limits.MaxPodsToEvictPerNode {
-			return fmt.Errorf("reached node %s eviction limit: %d",
-				pod.Spec.NodeName, limits.MaxPodsToEvictPerNode)
+			return &limitError{limit: limitPerNode, scope: pod.Spec.NodeName, max: limits.MaxPodsToEvictPerNode}
 		}
 	}
 
 	// 检查命名空间驱逐限制
 	if limits.MaxPodsToEvictPerNamespace > 0 {
 		if e.stats.EvictedByNamespace[pod.Namespace] >= limits.MaxPodsToEvictPerNamespace {
-			return fmt.Errorf("reached namespace %s eviction limit: %d",
-				pod.Namespace, limits.MaxPodsToEvictPerNamespace)
+			return &limitError{limit: limitPerNamespace, scope: pod.Namespace, max: limits.MaxPodsToEvictPerNamespace}
 		}
 	}
 
 	return nil
 }
 
+// EvictionLimits中各项限制的名称，与配置字段一致
+const (
+	limitTotal        = "maxPodsToEvictTotal"
+	limitPerNode      = "maxPodsToEvictPerNode"
+	limitPerNamespace = "maxPodsToEvictPerNamespace"
+)
+
+// limitError 达到驱逐限制
+type limitError struct {
+	// limit 达到的限制名称
+	limit string
+
+	// scope 节点或命名空间名称，总量限制时为空
+	scope string
+
+	// max 限制值
+	max int
+}
+
+// Error 实现error接口
+func (e *limitError) Error() string {
+	switch e.limit {
+	case limitPerNode:
+		return fmt.Sprintf("reached node %s eviction limit: %d", e.scope, e.max)
+	case limitPerNamespace:
+		return fmt.Sprintf("reached namespace %s eviction limit: %d", e.scope, e.max)
+	default:
+		return fmt.Sprintf("reached total eviction limit: %d", e.max)
+	}
+}
+
 // updateStats 更新驱逐统计信息
-func (e *DefaultPodEvictor) updateStats(pod *v1.Pod, reason string, success bool) {
+func (e *DefaultPodEvictor) updateStats(pod *v1.Pod, strategy, reason string, success bool) {
 	if success {
 		e.stats.TotalEvicted++
 		if pod.Spec.NodeName != "" {
@@ -230,30 +462,12 @@ func (e *DefaultPodEvictor) updateStats(pod *v1.Pod, reason string, success bool
 		}
 		e.stats.EvictedByNamespace[pod.Namespace]++
 		e.stats.EvictedByReason[reason]++
+		e.stats.EvictedByStrategy[strategy]++
 	}
 }
 
-// isSystemCriticalPod 检查是否是系统关键Pod
-func isSystemCriticalPod(pod *v1.Pod) bool {
-	// 检查优先级类
-	if pod.Spec.PriorityClassName == "system-cluster-critical" ||
-		pod.Spec.PriorityClassName == "system-node-critical" {
-		return true
-	}
-
-	// 检查系统命名空间
-	systemNamespaces := []string{"kube-system", "kube-public", "kube-node-lease"}
-	for _, ns := range systemNamespaces {
-		if pod.Namespace == ns {
-			return true
-		}
-	}
-
-	return false
-}
-
-// isDaemonSetPod 检查是否是DaemonSet的Pod
-func isDaemonSetPod(pod *v1.Pod) bool {
+// IsDaemonSetPod 检查是否是DaemonSet的Pod
+func IsDaemonSetPod(pod *v1.Pod) bool {
 	for _, owner := range pod.OwnerReferences {
 		if owner.Kind == "DaemonSet" {
 			return true
@@ -262,23 +476,8 @@ func isDaemonSetPod(pod *v1.Pod) bool {
 	return false
 }
 
-// isStaticPod 检查是否是静态Pod
-func isStaticPod(pod *v1.Pod) bool {
+// IsStaticPod 检查是否是静态Pod
+func IsStaticPod(pod *v1.Pod) bool {
 	source, ok := pod.Annotations["kubernetes.io/config.source"]
 	return ok && source == "file"
 }
-
-// isStandalonePod 检查是否是独立Pod（没有控制器）
-func isStandalonePod(pod *v1.Pod) bool {
-	return len(pod.OwnerReferences) == 0
-}
-
-// hasLocalStorage 检查Pod是否使用了本地存储
-func hasLocalStorage(pod *v1.Pod) bool {
-	for _, volume := range pod.Spec.Volumes {
-		if volume.HostPath != nil || volume.EmptyDir != nil {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,132 @@
+package eviction
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// disruptionBudget 单个PodDisruptionBudget在本轮循环中的预算信息
+type disruptionBudget struct {
+	key      string
+	selector labels.Selector
+
+	// allowed 本轮循环开始时PDB状态中允许的中断数量
+	allowed int
+
+	// used 本轮循环中已经消耗（驱逐或模拟驱逐）的中断数量
+	used int
+}
+
+// remaining 返回剩余可用的中断数量
+func (b *disruptionBudget) remaining() int {
+	return b.allowed - b.used
+}
+
+// RefreshPodDisruptionBudgets 重新加载所有PDB并清空本轮已消耗的预算
+func (e *DefaultPodEvictor) RefreshPodDisruptionBudgets(ctx context.Context) error {
+	pdbList, err := e.client.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pod disruption budgets: %v", err)
+	}
+
+	budgets := make(map[string][]*disruptionBudget)
+	for i := range pdbList.Items {
+		pdb := &pdbList.Items[i]
+
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			klog.Warningf("Ignoring pod disruption budget %s/%s with invalid selector: %v",
+				pdb.Namespace, pdb.Name, err)
+			continue
+		}
+
+		budgets[pdb.Namespace] = append(budgets[pdb.Namespace], &disruptionBudget{
+			key:      fmt.Sprintf("%s/%s", pdb.Namespace, pdb.Name),
+			selector: selector,
+			allowed:  int(pdb.Status.DisruptionsAllowed),
+		})
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.budgets = budgets
+
+	klog.V(2).Infof("Loaded %d pod disruption budgets", len(pdbList.Items))
+	return nil
+}
+
+// matchingBudgets 返回匹配指定Pod的所有PDB，调用方需持有锁
+func (e *DefaultPodEvictor) matchingBudgets(pod *v1.Pod) []*disruptionBudget {
+	var matched []*disruptionBudget
+	podLabels := labels.Set(pod.Labels)
+	for _, budget := range e.budgets[pod.Namespace] {
+		if budget.selector.Matches(podLabels) {
+			matched = append(matched, budget)
+		}
+	}
+	return matched
+}
+
+// checkDisruptionBudget 检查驱逐Pod是否会突破PDB，调用方需持有锁
+func (e *DefaultPodEvictor) checkDisruptionBudget(pod *v1.Pod) (bool, string) {
+	// 已终止的Pod不受PDB约束，Eviction API会直接删除
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return true, ""
+	}
+
+	budgets := e.matchingBudgets(pod)
+	if len(budgets) > 1 {
+		// Eviction API不支持被多个PDB覆盖的Pod
+		return false, fmt.Sprintf("pod is covered by %d pod disruption budgets", len(budgets))
+	}
+
+	for _, budget := range budgets {
+		if budget.remaining() <= 0 {
+			return false, fmt.Sprintf("pod disruption budget %s has no disruptions remaining (allowed: %d, used this cycle: %d)",
+				budget.key, budget.allowed, budget.used)
+		}
+	}
+
+	return true, ""
+}
+
+// checkDisruptionBudgets 检查一起驱逐所有Pod是否会突破任一PDB的剩余预算，调用方需持有锁
+func (e *DefaultPodEvictor) checkDisruptionBudgets(pods []*v1.Pod) (bool, string) {
+	needed := make(map[*disruptionBudget]int)
+	for _, pod := range pods {
+		if ok, reason := e.checkDisruptionBudget(pod); !ok {
+			return false, fmt.Sprintf("pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+		}
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, budget := range e.matchingBudgets(pod) {
+			needed[budget]++
+		}
+	}
+
+	for budget, count := range needed {
+		if count > budget.remaining() {
+			return false, fmt.Sprintf("evicting %d pods would exceed pod disruption budget %s (allowed: %d, used this cycle: %d)",
+				count, budget.key, budget.allowed, budget.used)
+		}
+	}
+
+	return true, ""
+}
+
+// consumeDisruptionBudget 记录驱逐消耗的PDB预算，调用方需持有锁
+func (e *DefaultPodEvictor) consumeDisruptionBudget(pod *v1.Pod) {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return
+	}
+
+	for _, budget := range e.matchingBudgets(pod) {
+		budget.used++
+	}
+}
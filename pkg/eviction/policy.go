@@ -0,0 +1,87 @@
+package eviction
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// 系统关键优先级类
+var systemCriticalPriorityClasses = []string{"system-cluster-critical", "system-node-critical"}
+
+// checkEvictionPolicy 按可驱逐性规则检查Pod，不可驱逐时返回的原因中包含触发的规则名称
+func checkEvictionPolicy(policy *config.EvictionPolicyConfig, pod *v1.Pod) (bool, string) {
+	if utils.Contains(policy.ProtectedNamespaces, pod.Namespace) {
+		return false, fmt.Sprintf("namespace %s is protected (evictionPolicy.protectedNamespaces)", pod.Namespace)
+	}
+
+	if !policy.EvictSystemCriticalPods && utils.Contains(systemCriticalPriorityClasses, pod.Spec.PriorityClassName) {
+		return false, fmt.Sprintf("system critical pod with priority class %s (evictionPolicy.evictSystemCriticalPods)",
+			pod.Spec.PriorityClassName)
+	}
+
+	if policy.PriorityThreshold != nil {
+		if priority := podPriority(pod); priority >= *policy.PriorityThreshold {
+			return false, fmt.Sprintf("priority %d is not below %d (evictionPolicy.priorityThreshold)",
+				priority, *policy.PriorityThreshold)
+		}
+	}
+
+	// 失败的独立Pod不会再运行，清理它们不受此规则限制
+	if !policy.EvictBarePods && isBarePod(pod) && pod.Status.Phase != v1.PodFailed {
+		return false, "bare pod without a controller (evictionPolicy.evictBarePods)"
+	}
+
+	if !policy.EvictLocalStoragePods {
+		if ok, reason := checkLocalStorage(policy, pod); !ok {
+			return false, reason
+		}
+	}
+
+	return true, ""
+}
+
+// checkLocalStorage 检查Pod的本地存储是否允许驱逐
+// hostPath始终阻止驱逐；启用EvictBoundedEmptyDirPods时，设置了sizeLimit或使用内存介质的emptyDir不阻止驱逐
+func checkLocalStorage(policy *config.EvictionPolicyConfig, pod *v1.Pod) (bool, string) {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			return false, fmt.Sprintf("pod has hostPath volume %s (evictionPolicy.evictLocalStoragePods)", volume.Name)
+		}
+		if volume.EmptyDir == nil {
+			continue
+		}
+		if !policy.EvictBoundedEmptyDirPods {
+			return false, fmt.Sprintf("pod has emptyDir volume %s (evictionPolicy.evictLocalStoragePods)", volume.Name)
+		}
+		if !isBoundedEmptyDir(volume.EmptyDir) {
+			return false, fmt.Sprintf("emptyDir volume %s has no sizeLimit and is not memory-backed (evictionPolicy.evictBoundedEmptyDirPods)",
+				volume.Name)
+		}
+	}
+	return true, ""
+}
+
+// isBoundedEmptyDir 检查emptyDir是否设置了sizeLimit或使用内存介质
+func isBoundedEmptyDir(emptyDir *v1.EmptyDirVolumeSource) bool {
+	if emptyDir.Medium == v1.StorageMediumMemory {
+		return true
+	}
+	return emptyDir.SizeLimit != nil && !emptyDir.SizeLimit.IsZero()
+}
+
+// isBarePod 检查是否是没有控制器的独立Pod
+func isBarePod(pod *v1.Pod) bool {
+	return len(pod.OwnerReferences) == 0
+}
+
+// podPriority 返回Pod的优先级，未设置时为0
+func podPriority(pod *v1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
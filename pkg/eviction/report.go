@@ -0,0 +1,272 @@
+package eviction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+	v1 "k8s.io/api/core/v1"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// DryRunReport 一次循环的驱逐计划报告，列出DryRun或只观察模式下本应驱逐的Pod
+type DryRunReport struct {
+	CycleID string `json:"cycleId" yaml:"cycleId"`
+
+	// TotalPods 本应驱逐的Pod总数
+	TotalPods int `json:"totalPods" yaml:"totalPods"`
+
+	// Strategies 按策略执行顺序排列的驱逐计划
+	Strategies []StrategyPlan `json:"strategies" yaml:"strategies"`
+
+	// Nodes 有Pod本应被驱逐的节点在模拟驱逐前后基于资源请求的利用率百分比
+	Nodes []NodeUtilizationChange `json:"nodes" yaml:"nodes"`
+
+	// LimitsHit 本次循环达到的驱逐限制
+	LimitsHit []LimitHit `json:"limitsHit,omitempty" yaml:"limitsHit,omitempty"`
+}
+
+// StrategyPlan 单个策略的驱逐计划
+type StrategyPlan struct {
+	Strategy string `json:"strategy" yaml:"strategy"`
+
+	// ObserveOnly 策略是否因驱逐时间安排处于只观察模式
+	ObserveOnly bool `json:"observeOnly,omitempty" yaml:"observeOnly,omitempty"`
+
+	// Nodes 按节点名称排列的驱逐计划
+	Nodes []NodePlan `json:"nodes" yaml:"nodes"`
+}
+
+// NodePlan 单个节点上本应驱逐的Pod
+type NodePlan struct {
+	Node string            `json:"node" yaml:"node"`
+	Pods []PlannedEviction `json:"pods" yaml:"pods"`
+}
+
+// PlannedEviction 本应驱逐的Pod
+type PlannedEviction struct {
+	Pod       string `json:"pod" yaml:"pod"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Owner     string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Reason    string `json:"reason" yaml:"reason"`
+}
+
+// NodeUtilizationChange 节点在模拟驱逐前后的利用率
+// 驱逐后的利用率只扣除了被驱逐Pod的资源请求，不包括Pod重新调度到的节点，因为目标节点由调度器决定
+type NodeUtilizationChange struct {
+	Node   string                  `json:"node" yaml:"node"`
+	Before map[v1.ResourceName]int `json:"before" yaml:"before"`
+	After  map[v1.ResourceName]int `json:"after" yaml:"after"`
+}
+
+// LimitHit 达到的驱逐限制
+type LimitHit struct {
+	// Limit EvictionLimits中的配置字段名称
+	Limit string `json:"limit" yaml:"limit"`
+
+	// Scope 节点或命名空间名称，总量限制时为空
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty"`
+
+	// Max 限制值
+	Max int `json:"max" yaml:"max"`
+
+	// RefusedPods 因此限制没有驱逐的Pod数量
+	RefusedPods int `json:"refusedPods" yaml:"refusedPods"`
+}
+
+// plannedEviction 本次循环中记录的模拟驱逐
+type plannedEviction struct {
+	strategy    string
+	observeOnly bool
+	node        string
+	pod         PlannedEviction
+}
+
+// planEviction 记录一次模拟驱逐并从节点的模拟利用率中扣除Pod的资源请求，需持有e.mu
+func (e *DefaultPodEvictor) planEviction(pod *v1.Pod, strategy, reason string, observeOnly bool) {
+	if e.simulated == nil {
+		// BeginCycle之前没有循环上下文，不记录
+		return
+	}
+
+	e.plan = append(e.plan, plannedEviction{
+		strategy:    strategy,
+		observeOnly: observeOnly,
+		node:        pod.Spec.NodeName,
+		pod: PlannedEviction{
+			Pod:       pod.Name,
+			Namespace: pod.Namespace,
+			Owner:     podOwner(pod),
+			Reason:    reason,
+		},
+	})
+
+	if e.snapshot == nil || pod.Spec.NodeName == "" {
+		return
+	}
+	// 首次访问时计算驱逐前的利用率，以便报告中的before与after对应
+	e.nodeUtilization(pod.Spec.NodeName)
+
+	simulated, ok := e.simulated[pod.Spec.NodeName]
+	if !ok {
+		node := e.snapshot.Node(pod.Spec.NodeName)
+		if node == nil {
+			return
+		}
+		simulated = utils.CalculateNodeUtilization(node, e.snapshot.PodsOnNode(node.Name))
+		e.simulated[node.Name] = simulated
+	}
+	simulated.RemovePod(utils.PodRequests(pod))
+}
+
+// DryRunReport 返回本次循环的驱逐计划报告
+func (e *DefaultPodEvictor) DryRunReport() *DryRunReport {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	report := &DryRunReport{
+		CycleID:    e.cycleID,
+		TotalPods:  len(e.plan),
+		Strategies: []StrategyPlan{},
+		Nodes:      []NodeUtilizationChange{},
+	}
+
+	// 策略按首次出现的顺序（即执行顺序）排列，节点按名称排列
+	strategyIndex := make(map[string]int)
+	podsByStrategyNode := make(map[string]map[string][]PlannedEviction)
+	for _, planned := range e.plan {
+		if _, ok := strategyIndex[planned.strategy]; !ok {
+			strategyIndex[planned.strategy] = len(report.Strategies)
+			report.Strategies = append(report.Strategies, StrategyPlan{
+				Strategy:    planned.strategy,
+				ObserveOnly: planned.observeOnly,
+			})
+			podsByStrategyNode[planned.strategy] = make(map[string][]PlannedEviction)
+		}
+		podsByStrategyNode[planned.strategy][planned.node] = append(
+			podsByStrategyNode[planned.strategy][planned.node], planned.pod)
+	}
+	for i := range report.Strategies {
+		for node, pods := range podsByStrategyNode[report.Strategies[i].Strategy] {
+			report.Strategies[i].Nodes = append(report.Strategies[i].Nodes, NodePlan{Node: node, Pods: pods})
+		}
+		nodes := report.Strategies[i].Nodes
+		sort.Slice(nodes, func(a, b int) bool { return nodes[a].Node < nodes[b].Node })
+	}
+
+	for node, simulated := range e.simulated {
+		report.Nodes = append(report.Nodes, NodeUtilizationChange{
+			Node:   node,
+			Before: e.utilizations[node],
+			After:  simulated.Percent,
+		})
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Node < report.Nodes[j].Node })
+
+	for hit, refused := range e.limitsHit {
+		report.LimitsHit = append(report.LimitsHit, LimitHit{
+			Limit:       hit.limit,
+			Scope:       hit.scope,
+			Max:         hit.max,
+			RefusedPods: refused,
+		})
+	}
+	sort.Slice(report.LimitsHit, func(i, j int) bool {
+		if report.LimitsHit[i].Limit != report.LimitsHit[j].Limit {
+			return report.LimitsHit[i].Limit < report.LimitsHit[j].Limit
+		}
+		return report.LimitsHit[i].Scope < report.LimitsHit[j].Scope
+	})
+
+	return report
+}
+
+// Write 按指定格式 (table, json, yaml) 输出报告
+func (r *DryRunReport) Write(w io.Writer, format string) error {
+	switch format {
+	case config.ReportFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case config.ReportFormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case config.ReportFormatTable:
+		return r.writeTable(w)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// writeTable 以表格形式输出报告
+func (r *DryRunReport) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if r.TotalPods == 0 {
+		fmt.Fprintf(tw, "Dry-run eviction plan for cycle %s: no pods would be evicted\n", r.CycleID)
+	} else {
+		fmt.Fprintf(tw, "Dry-run eviction plan for cycle %s: %d pods would be evicted\n\n", r.CycleID, r.TotalPods)
+		fmt.Fprintln(tw, "STRATEGY\tNODE\tPOD\tOWNER\tREASON")
+		for _, strategy := range r.Strategies {
+			name := strategy.Strategy
+			if strategy.ObserveOnly {
+				name += " (observe-only)"
+			}
+			for _, node := range strategy.Nodes {
+				for _, pod := range node.Pods {
+					fmt.Fprintf(tw, "%s\t%s\t%s/%s\t%s\t%s\n",
+						name, node.Node, pod.Namespace, pod.Pod, valueOrNone(pod.Owner), pod.Reason)
+				}
+			}
+		}
+	}
+
+	if len(r.Nodes) > 0 {
+		fmt.Fprintln(tw, "\nNODE\tUTILIZATION BEFORE\tUTILIZATION AFTER")
+		for _, node := range r.Nodes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", node.Node, formatPercents(node.Before), formatPercents(node.After))
+		}
+	}
+
+	if len(r.LimitsHit) > 0 {
+		fmt.Fprintln(tw, "\nLIMIT\tSCOPE\tMAX\tREFUSED PODS")
+		for _, hit := range r.LimitsHit {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", hit.Limit, valueOrNone(hit.Scope), hit.Max, hit.RefusedPods)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// formatPercents 按资源名称排序输出利用率，如 "cpu=20%, memory=35%"
+func formatPercents(percents map[v1.ResourceName]int) string {
+	names := make([]string, 0, len(percents))
+	for name := range percents {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d%%", name, percents[v1.ResourceName(name)]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// valueOrNone 空值在表格中显示为<none>
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,146 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+)
+
+// ElectionConfig 选主配置
+type ElectionConfig struct {
+	// LeaseName Lease对象名称
+	LeaseName string
+
+	// LeaseNamespace Lease对象所在的命名空间
+	LeaseNamespace string
+
+	// Identity 当前实例的唯一标识，为空时使用主机名
+	Identity string
+
+	// LeaseDuration 非Leader等待多久后可以尝试抢占Lease
+	LeaseDuration time.Duration
+
+	// RenewDeadline Leader在放弃领导权之前尝试续约的时间
+	RenewDeadline time.Duration
+
+	// RetryPeriod 各实例尝试获取或续约Lease的间隔
+	RetryPeriod time.Duration
+}
+
+// Validate 验证选主配置
+func (c *ElectionConfig) Validate() error {
+	if c.LeaseName == "" {
+		return fmt.Errorf("lease name must not be empty")
+	}
+	if c.LeaseNamespace == "" {
+		return fmt.Errorf("lease namespace must not be empty")
+	}
+	if c.LeaseDuration <= c.RenewDeadline {
+		return fmt.Errorf("lease duration (%v) must be greater than renew deadline (%v)",
+			c.LeaseDuration, c.RenewDeadline)
+	}
+	if c.RetryPeriod <= 0 || c.RenewDeadline <= c.RetryPeriod {
+		return fmt.Errorf("renew deadline (%v) must be greater than retry period (%v)",
+			c.RenewDeadline, c.RetryPeriod)
+	}
+	return nil
+}
+
+// Run 参与Lease选主，只有成为Leader后才执行run
+// 当ctx被取消或run正常返回时，Run会释放Lease并返回run的结果；
+// 如果在run运行期间失去领导权，Run返回错误，调用方应当退出进程
+func Run(ctx context.Context, client kubernetes.Interface, cfg ElectionConfig, run func(ctx context.Context) error) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid leader election config: %v", err)
+	}
+
+	identity := cfg.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to get hostname for leader election identity: %v", err)
+		}
+		identity = hostname
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      cfg.LeaseName,
+			Namespace: cfg.LeaseNamespace,
+		},
+		Client: client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// OnStartedLeading 在独立goroutine中被调用，这里只把leader上下文交给调用方，
+	// 由调用方所在的goroutine执行run，便于在失去领导权时等待run退出
+	leaderCtxCh := make(chan context.Context, 1)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   cfg.LeaseDuration,
+		RenewDeadline:   cfg.RenewDeadline,
+		RetryPeriod:     cfg.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            cfg.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				klog.Infof("Acquired leader lease %s/%s as %s", cfg.LeaseNamespace, cfg.LeaseName, identity)
+				leaderCtxCh <- leaderCtx
+				<-leaderCtx.Done()
+			},
+			OnStoppedLeading: func() {
+				klog.Infof("Stopped leading lease %s/%s", cfg.LeaseNamespace, cfg.LeaseName)
+			},
+			OnNewLeader: func(current string) {
+				if current != identity {
+					klog.Infof("Current leader is %s, waiting in standby", current)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create leader elector: %v", err)
+	}
+
+	klog.Infof("Starting leader election for lease %s/%s as %s", cfg.LeaseNamespace, cfg.LeaseName, identity)
+	electionDone := make(chan struct{})
+	go func() {
+		defer close(electionDone)
+		elector.Run(electionCtx)
+	}()
+
+	select {
+	case <-electionDone:
+		// 在获得领导权之前选主就结束了
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("leader election for lease %s/%s stopped before acquiring it",
+			cfg.LeaseNamespace, cfg.LeaseName)
+	case leaderCtx := <-leaderCtxCh:
+		runErr := run(leaderCtx)
+		lost := leaderCtx.Err() != nil && ctx.Err() == nil
+
+		// 停止选主并释放Lease，让备用实例尽快接管
+		cancel()
+		<-electionDone
+
+		if lost {
+			return fmt.Errorf("lost leader lease %s/%s", cfg.LeaseNamespace, cfg.LeaseName)
+		}
+		return runErr
+	}
+}
@@ -0,0 +1,130 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	testLeaseName      = "lightweight-descheduler"
+	testLeaseNamespace = "kube-system"
+)
+
+func testElectionConfig(identity string) ElectionConfig {
+	return ElectionConfig{
+		LeaseName:      testLeaseName,
+		LeaseNamespace: testLeaseNamespace,
+		Identity:       identity,
+		LeaseDuration:  2 * time.Second,
+		RenewDeadline:  1 * time.Second,
+		RetryPeriod:    200 * time.Millisecond,
+	}
+}
+
+func getLease(t *testing.T, client kubernetes.Interface) *coordinationv1.Lease {
+	t.Helper()
+	lease, err := client.CoordinationV1().Leases(testLeaseNamespace).Get(context.Background(), testLeaseName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get lease: %v", err)
+	}
+	return lease
+}
+
+func holderIdentity(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+func TestRunAcquiresLeaseAndReleasesOnCancel(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	holder := make(chan string, 1)
+	run := func(leaderCtx context.Context) error {
+		holder <- holderIdentity(getLease(t, client))
+		<-leaderCtx.Done()
+		return leaderCtx.Err()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, client, testElectionConfig("replica-a"), run)
+	}()
+
+	select {
+	case identity := <-holder:
+		if identity != "replica-a" {
+			t.Errorf("lease holder while leading = %q, want %q", identity, "replica-a")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting to acquire the lease")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for Run to return after cancel")
+	}
+
+	if identity := holderIdentity(getLease(t, client)); identity != "" {
+		t.Errorf("lease holder after cancel = %q, want the lease to be released", identity)
+	}
+}
+
+func TestRunStandbyDoesNotRun(t *testing.T) {
+	// 另一个副本持有一个仍然有效的Lease
+	other := "replica-a"
+	durationSeconds := int32(60)
+	now := metav1.NewMicroTime(time.Now())
+	client := fake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: testLeaseName, Namespace: testLeaseNamespace},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &other,
+			LeaseDurationSeconds: &durationSeconds,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var ran atomic.Bool
+	err := Run(ctx, client, testElectionConfig("replica-b"), func(context.Context) error {
+		ran.Store(true)
+		return nil
+	})
+
+	if ran.Load() {
+		t.Errorf("standby replica ran the descheduling loop")
+	}
+	if err != context.DeadlineExceeded {
+		t.Errorf("Run returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if identity := holderIdentity(getLease(t, client)); identity != other {
+		t.Errorf("lease holder = %q, want %q", identity, other)
+	}
+}
+
+func TestElectionConfigValidate(t *testing.T) {
+	cfg := testElectionConfig("replica-a")
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+
+	cfg.RenewDeadline = cfg.LeaseDuration
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("config with renew deadline equal to lease duration accepted")
+	}
+}
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+const namespace = "descheduler"
+
+var (
+	// Registry 重调度器专用的指标注册表
+	Registry = prometheus.NewRegistry()
+
+	// PodsEvicted 累计驱逐的Pod数量
+	PodsEvicted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pods_evicted_total",
+			Help:      "Number of pods evicted by the descheduler.",
+		},
+		[]string{"node", "namespace", "strategy", "reason", "dry_run"},
+	)
+
+	// PodEvictionsFailed 累计驱逐失败的Pod数量
+	PodEvictionsFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pod_evictions_failed_total",
+			Help:      "Number of pod evictions rejected by the API server.",
+		},
+		[]string{"node", "namespace", "strategy"},
+	)
+
+	// CyclesTotal 累计执行的重调度循环次数
+	CyclesTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cycles_total",
+			Help:      "Number of descheduling cycles started.",
+		},
+	)
+
+	// CycleDuration 重调度循环耗时
+	CycleDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cycle_duration_seconds",
+			Help:      "Duration of a full descheduling cycle.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		},
+	)
+
+	// StrategyDuration 单个策略执行耗时
+	StrategyDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "strategy_duration_seconds",
+			Help:      "Duration of a single strategy execution.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+		},
+		[]string{"strategy"},
+	)
+
+	// StrategyErrors 策略执行失败次数
+	StrategyErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "strategy_errors_total",
+			Help:      "Number of strategy executions that returned an error.",
+		},
+		[]string{"strategy"},
+	)
+
+	// StrategyObserveOnly 策略在最近一次循环中是否因驱逐时间安排处于只观察模式
+	StrategyObserveOnly = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "strategy_observe_only",
+			Help:      "Whether the strategy ran in observe-only mode in the last cycle because it was outside its eviction schedule (1) or not (0).",
+		},
+		[]string{"strategy"},
+	)
+
+	// CycleTriggers 事件触发的重调度请求次数
+	CycleTriggers = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cycle_triggers_total",
+			Help:      "Number of cluster events that requested a descheduling cycle.",
+		},
+		[]string{"trigger"},
+	)
+
+	// ConfigReloads 配置重新加载次数
+	ConfigReloads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "config_reloads_total",
+			Help:      "Number of configuration reloads, by result (applied or rejected).",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	Registry.MustRegister(
+		PodsEvicted,
+		PodEvictionsFailed,
+		CyclesTotal,
+		CycleDuration,
+		StrategyDuration,
+		StrategyErrors,
+		StrategyObserveOnly,
+		CycleTriggers,
+		ConfigReloads,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RecordEviction 记录一次成功（或DryRun）的驱逐
+func RecordEviction(node, namespace, strategy, reason string, dryRun bool) {
+	PodsEvicted.WithLabelValues(node, namespace, strategy, ReasonLabel(reason), strconv.FormatBool(dryRun)).Inc()
+}
+
+// RecordFailedEviction 记录一次失败的驱逐
+func RecordFailedEviction(node, namespace, strategy string) {
+	PodEvictionsFailed.WithLabelValues(node, namespace, strategy).Inc()
+}
+
+// ReasonLabel 将驱逐原因归一化为低基数的标签值
+// 驱逐原因约定为 "概要 - 详情" 的格式，只保留概要部分
+func ReasonLabel(reason string) string {
+	if idx := strings.Index(reason, " - "); idx >= 0 {
+		return reason[:idx]
+	}
+	return reason
+}
+
+// Handler 返回暴露指标的HTTP处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Serve 在指定地址上启动指标HTTP服务，直到ctx被取消
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Failed to shut down metrics server: %v", err)
+		}
+	}()
+
+	klog.Infof("Serving metrics on %s/metrics", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// 运行镜像基于scratch，没有系统时区数据，内嵌一份供LoadLocation使用
+	_ "time/tzdata"
+)
+
+// Window 允许驱逐的时间窗口
+type Window struct {
+	// Days 生效的星期，如 ["Mon", "Tue"]，为空时每天生效
+	// 跨午夜的窗口按开始时间所在的那天判断
+	Days []string `yaml:"days,omitempty"`
+
+	// Start 开始时间（含），格式 HH:MM
+	Start string `yaml:"start"`
+
+	// End 结束时间（不含），格式 HH:MM，早于或等于Start时表示跨越午夜
+	End string `yaml:"end"`
+}
+
+// Schedule 驱逐时间安排，由cron表达式或时间窗口列表定义
+type Schedule struct {
+	location    *time.Location
+	cron        *cronExpression
+	windows     []window
+	description string
+}
+
+// window 解析后的时间窗口，时间以一天中的分钟数表示
+type window struct {
+	days  map[time.Weekday]bool
+	start int
+	end   int
+}
+
+// Parse 解析驱逐时间安排，cron和windows必须且只能设置其中一个，timezone为空时使用UTC
+func Parse(timezone, cron string, windows []Window) (*Schedule, error) {
+	if cron == "" && len(windows) == 0 {
+		return nil, fmt.Errorf("either cron or windows must be set")
+	}
+	if cron != "" && len(windows) > 0 {
+		return nil, fmt.Errorf("cron and windows are mutually exclusive")
+	}
+
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %v", timezone, err)
+	}
+
+	schedule := &Schedule{location: location}
+
+	if cron != "" {
+		expr, err := parseCron(cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %v", cron, err)
+		}
+		schedule.cron = expr
+		schedule.description = fmt.Sprintf("cron %q (%s)", cron, timezone)
+		return schedule, nil
+	}
+
+	var descriptions []string
+	for i, w := range windows {
+		parsed, err := parseWindow(w)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %d: %v", i, err)
+		}
+		schedule.windows = append(schedule.windows, parsed)
+
+		desc := fmt.Sprintf("%s-%s", w.Start, w.End)
+		if len(w.Days) > 0 {
+			desc = strings.Join(w.Days, ",") + " " + desc
+		}
+		descriptions = append(descriptions, desc)
+	}
+	schedule.description = fmt.Sprintf("windows [%s] (%s)", strings.Join(descriptions, "; "), timezone)
+
+	return schedule, nil
+}
+
+// Allows 检查指定时间是否允许驱逐
+func (s *Schedule) Allows(t time.Time) bool {
+	t = t.In(s.location)
+
+	if s.cron != nil {
+		return s.cron.matches(t)
+	}
+
+	for _, w := range s.windows {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxCronLookback AllowsSince检查cron表达式时最多回溯的时间
+const maxCronLookback = 7 * 24 * time.Hour
+
+// AllowsSince 检查本次循环是否允许驱逐，prev为上次循环的时间
+// 触发点式的cron表达式（如 "0 2 * * *"）只要从上次循环（不含）到本次循环（含）之间有任一分钟匹配就允许，
+// 不要求循环恰好在02:00开始；窗口式的cron表达式（如 "* 0-6 * * *"）和时间窗口按本次循环的时间判断，
+// 窗口结束后不会再驱逐。prev为零值（第一次循环）时等同于Allows
+func (s *Schedule) AllowsSince(prev, t time.Time) bool {
+	if s.cron == nil || !s.cron.trigger || prev.IsZero() || !prev.Before(t) {
+		return s.Allows(t)
+	}
+
+	if t.Sub(prev) > maxCronLookback {
+		prev = t.Add(-maxCronLookback)
+	}
+
+	// 从prev之后的第一个整分钟开始，逐分钟检查到t所在的分钟
+	t = t.In(s.location)
+	minute := prev.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	for ; !minute.After(t); minute = minute.Add(time.Minute) {
+		if s.cron.matches(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// String 返回时间安排的描述，用于日志
+func (s *Schedule) String() string {
+	return s.description
+}
+
+// contains 检查时间是否落在窗口内
+func (w window) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+
+	if w.start < w.end {
+		return w.matchesDay(t.Weekday()) && minute >= w.start && minute < w.end
+	}
+
+	// 跨午夜的窗口：开始当天的晚上，或开始后一天的早上
+	if minute >= w.start {
+		return w.matchesDay(t.Weekday())
+	}
+	if minute < w.end {
+		return w.matchesDay((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+// matchesDay 检查星期是否在窗口生效的日期内
+func (w window) matchesDay(day time.Weekday) bool {
+	return len(w.days) == 0 || w.days[day]
+}
+
+// parseWindow 解析时间窗口
+func parseWindow(w Window) (window, error) {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return window{}, fmt.Errorf("invalid start: %v", err)
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return window{}, fmt.Errorf("invalid end: %v", err)
+	}
+
+	parsed := window{start: start, end: end}
+	if len(w.Days) > 0 {
+		parsed.days = make(map[time.Weekday]bool, len(w.Days))
+		for _, day := range w.Days {
+			weekday, err := parseWeekday(day)
+			if err != nil {
+				return window{}, err
+			}
+			parsed.days[weekday] = true
+		}
+	}
+
+	return parsed, nil
+}
+
+// parseClock 解析 HH:MM 格式的时间，返回一天中的分钟数
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not in HH:MM format", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseWeekday 解析星期名称，支持英文全称和三字母缩写，不区分大小写
+func parseWeekday(value string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := day.String()
+		if strings.EqualFold(value, name) || strings.EqualFold(value, name[:3]) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", value)
+}
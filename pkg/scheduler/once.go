@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+)
+
+// CycleResult 一次重调度循环的结果
+type CycleResult struct {
+	// Strategies 本次循环执行的策略
+	Strategies []string
+
+	// FailedStrategies 执行失败的策略
+	FailedStrategies []string
+
+	// RefusedByLimits 因达到驱逐限制没有驱逐的Pod数量
+	RefusedByLimits int
+}
+
+// RunOnce 执行一次重调度循环后返回，用于CronJob等单次运行的部署方式
+// 单次运行时不使用运行间隔、事件触发和配置热加载
+func (s *Scheduler) RunOnce(ctx context.Context) (*CycleResult, error) {
+	klog.Infof("Starting lightweight descheduler for a single cycle")
+	klog.Infof("Configuration: DryRun=%v", s.config.DryRun)
+
+	if s.config.DryRun {
+		klog.Infof("Running in DRY RUN mode - no pods will actually be evicted")
+	}
+	if s.triggers != nil {
+		klog.Warningf("Event triggers are ignored when running a single cycle")
+	}
+	defer s.close()
+
+	if err := s.cache.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start cache: %v", err)
+	}
+
+	return s.runOnce(ctx)
+}
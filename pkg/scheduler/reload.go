@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/eviction"
+	"lightweight-descheduler/pkg/metrics"
+	"lightweight-descheduler/pkg/strategies"
+)
+
+// 配置重新加载结果，同时用作指标标签
+const (
+	reloadApplied  = "applied"
+	reloadRejected = "rejected"
+)
+
+// WatchConfig 启用配置热加载
+// 每隔pollInterval检查配置文件内容是否变化，pollInterval为0时只在RequestReload时重新加载。
+// 按内容而不是修改时间判断变化，ConfigMap挂载通过替换符号链接更新文件时也能发现
+func (s *Scheduler) WatchConfig(path string, pollInterval time.Duration) error {
+	hash, err := fileHash(path)
+	if err != nil {
+		return err
+	}
+
+	s.configPath = path
+	s.configHash = hash
+	s.configPollInterval = pollInterval
+	return nil
+}
+
+// RequestReload 请求重新加载配置文件，在下一个循环间隙执行，用于处理SIGHUP
+func (s *Scheduler) RequestReload() {
+	if s.configPath == "" {
+		klog.Warningf("Configuration reload requested but no configuration file is being watched")
+		return
+	}
+
+	select {
+	case s.reloadRequests <- struct{}{}:
+	default:
+	}
+}
+
+// configChanged 检查配置文件内容自上次加载以来是否变化
+func (s *Scheduler) configChanged() bool {
+	hash, err := fileHash(s.configPath)
+	if err != nil {
+		klog.Errorf("Failed to check configuration file for changes: %v", err)
+		return false
+	}
+	if hash == s.configHash {
+		return false
+	}
+
+	// 无论新配置是否有效都记录当前内容，无效配置只报告一次
+	s.configHash = hash
+	klog.Infof("Configuration file %s changed, reloading", s.configPath)
+	return true
+}
+
+// reloadConfig 重新加载并验证配置文件
+// 配置有效时替换当前配置并重建策略，无效时保留当前配置。只在两次循环之间调用，循环总是使用完整的一套配置
+func (s *Scheduler) reloadConfig(ticker *time.Ticker) {
+	interval := s.config.Interval
+
+	cfg, err := config.LoadConfig(s.configPath)
+	if err == nil {
+		err = s.applyConfig(cfg)
+	}
+	if err != nil {
+		metrics.ConfigReloads.WithLabelValues(reloadRejected).Inc()
+		klog.Errorf("Rejected configuration from %s, keeping the last good configuration: %v", s.configPath, err)
+		return
+	}
+
+	metrics.ConfigReloads.WithLabelValues(reloadApplied).Inc()
+	if s.config.Interval != interval {
+		ticker.Reset(s.config.Interval)
+	}
+}
+
+// applyConfig 使用新配置重建驱逐器和策略，全部创建成功后才替换当前的组件
+func (s *Scheduler) applyConfig(cfg *config.Config) error {
+	// 事件触发的处理函数在启动时注册到缓存，修改需要重启才能生效
+	if !reflect.DeepEqual(cfg.Triggers, s.config.Triggers) {
+		klog.Warningf("Changes to triggers take effect only after a restart, keeping the current triggers")
+		cfg.Triggers = s.config.Triggers
+	}
+	if cfg.Mode != s.config.Mode {
+		klog.Warningf("Changes to mode take effect only after a restart, keeping mode %s", s.config.Mode)
+		cfg.Mode = s.config.Mode
+	}
+
+	auditSink := s.auditSink
+	if !reflect.DeepEqual(cfg.Audit, s.config.Audit) {
+		auditSink = nil
+		if cfg.Audit != nil && cfg.Audit.Enabled {
+			sink, err := eviction.NewAuditSink(cfg.Audit)
+			if err != nil {
+				return fmt.Errorf("failed to create audit sink: %v", err)
+			}
+			auditSink = sink
+		}
+	}
+
+	evictor := eviction.NewDefaultPodEvictor(s.client, cfg, s.recorder, auditSink)
+	strategyFactory := strategies.NewStrategyFactory(s.client, s.metricsClient, cfg, evictor)
+	enabledStrategies := strategyFactory.CreateStrategies()
+
+	schedules, err := buildSchedules(cfg, enabledStrategies)
+	if err != nil {
+		if auditSink != nil && auditSink != s.auditSink {
+			auditSink.Close()
+		}
+		return err
+	}
+
+	if s.auditSink != nil && s.auditSink != auditSink {
+		if err := s.auditSink.Close(); err != nil {
+			klog.Errorf("Failed to close previous audit sink: %v", err)
+		}
+	}
+
+	s.config = cfg
+	s.evictor = evictor
+	s.auditSink = auditSink
+	s.strategies = enabledStrategies
+	s.schedules = schedules
+	s.strategyContext = strategyFactory.Context()
+
+	// 已停用的策略不再上报只观察状态
+	metrics.StrategyObserveOnly.Reset()
+
+	klog.Infof("Applied configuration from %s: DryRun=%v, Interval=%v, %d enabled strategies",
+		s.configPath, cfg.DryRun, cfg.Interval, len(enabledStrategies))
+	for _, strategy := range enabledStrategies {
+		klog.Infof("  - %s", strategy.Name())
+	}
+	return nil
+}
+
+// fileHash 计算文件内容的摘要
+func fileHash(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("failed to read config file: %v", err)
+	}
+	return sha256.Sum256(data), nil
+}
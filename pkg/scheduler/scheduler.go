@@ -2,44 +2,126 @@ package scheduler
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	"lightweight-descheduler/pkg/config"
 	"lightweight-descheduler/pkg/eviction"
+	"lightweight-descheduler/pkg/metrics"
+	"lightweight-descheduler/pkg/schedule"
+	"lightweight-descheduler/pkg/snapshot"
 	"lightweight-descheduler/pkg/strategies"
 	"lightweight-descheduler/pkg/utils"
 )
 
 // Scheduler 轻量级重调度器
 type Scheduler struct {
-	client     kubernetes.Interface
-	config     *config.Config
-	evictor    eviction.PodEvictor
-	strategies []strategies.Strategy
+	client        kubernetes.Interface
+	metricsClient metricsclientset.Interface
+	config        *config.Config
+	evictor       eviction.PodEvictor
+	cache         *snapshot.Cache
+	strategies    []strategies.Strategy
+
+	// recorder 在Pod上记录驱逐事件，重新加载配置时复用
+	recorder record.EventRecorder
+
+	// stopEvents 等待已记录的事件写入后停止事件广播
+	stopEvents func()
+
+	// auditSink 驱逐决策审计记录输出目标，未启用时为nil
+	auditSink eviction.AuditSink
+
+	// triggers 事件触发器，未启用时为nil
+	triggers *triggerWatcher
+
+	// schedules 按策略名称索引的驱逐时间安排，没有时间安排的策略不在其中
+	schedules map[string]*schedule.Schedule
+
+	// lastCycleStart 上一次循环的开始时间，用于判断两次循环之间是否经过了cron表达式匹配的时间
+	lastCycleStart time.Time
+
+	// strategyContext 策略共享的执行上下文，每次循环开始时更新其中的快照
+	strategyContext *strategies.StrategyContext
+
+	// configPath 热加载监视的配置文件，为空时不热加载
+	configPath string
+
+	// configHash 最近一次加载的配置文件内容摘要
+	configHash [sha256.Size]byte
+
+	// configPollInterval 检查配置文件变化的间隔，为0时只在收到请求时重新加载
+	configPollInterval time.Duration
+
+	// reloadRequests 重新加载配置的请求，容量为1
+	reloadRequests chan struct{}
 }
 
 // NewScheduler 创建新的重调度器
-func NewScheduler(client kubernetes.Interface, cfg *config.Config) (*Scheduler, error) {
-	// 创建Pod驱逐器
-	evictor := eviction.NewDefaultPodEvictor(client, cfg)
+func NewScheduler(client kubernetes.Interface, metricsClient metricsclientset.Interface, cfg *config.Config) (*Scheduler, error) {
+	// 创建Pod和节点缓存
+	podCache, err := snapshot.NewCache(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache: %v", err)
+	}
+
+	// 创建事件触发器，需在缓存启动前注册事件处理函数
+	var triggers *triggerWatcher
+	if cfg.Triggers != nil && cfg.Triggers.Enabled {
+		triggers, err = newTriggerWatcher(cfg.Triggers, podCache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create triggers: %v", err)
+		}
+	}
+
+	// 创建驱逐决策审计记录输出目标
+	var auditSink eviction.AuditSink
+	if cfg.Audit != nil && cfg.Audit.Enabled {
+		auditSink, err = eviction.NewAuditSink(cfg.Audit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create audit sink: %v", err)
+		}
+	}
+
+	// 创建Pod驱逐器，驱逐相关的事件记录在Pod上
+	recorder, stopEvents := eviction.NewEventRecorder(client)
+	evictor := eviction.NewDefaultPodEvictor(client, cfg, recorder, auditSink)
 
 	// 创建策略工厂
-	strategyFactory := strategies.NewStrategyFactory(client, cfg, evictor)
+	strategyFactory := strategies.NewStrategyFactory(client, metricsClient, cfg, evictor)
 
 	// 创建所有启用的策略
 	enabledStrategies := strategyFactory.CreateStrategies()
 
 	scheduler := &Scheduler{
-		client:     client,
-		config:     cfg,
-		evictor:    evictor,
-		strategies: enabledStrategies,
+		client:          client,
+		metricsClient:   metricsClient,
+		config:          cfg,
+		evictor:         evictor,
+		cache:           podCache,
+		strategies:      enabledStrategies,
+		recorder:        recorder,
+		stopEvents:      stopEvents,
+		reloadRequests:  make(chan struct{}, 1),
+		auditSink:       auditSink,
+		triggers:        triggers,
+		strategyContext: strategyFactory.Context(),
+	}
+
+	scheduler.schedules, err = buildSchedules(cfg, enabledStrategies)
+	if err != nil {
+		scheduler.close()
+		return nil, err
 	}
 
 	klog.Infof("Created scheduler with %d enabled strategies", len(enabledStrategies))
@@ -58,10 +140,11 @@ func (s *Scheduler) Run(ctx context.Context) error {
 	if s.config.DryRun {
 		klog.Infof("Running in DRY RUN mode - no pods will actually be evicted")
 	}
+	defer s.close()
 
-	// 如果间隔为0，只运行一次
-	if s.config.Interval == 0 {
-		return s.runOnce(ctx)
+	// 启动缓存，之后每次循环都从缓存生成快照
+	if err := s.cache.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start cache: %v", err)
 	}
 
 	// 定期运行
@@ -69,9 +152,28 @@ func (s *Scheduler) Run(ctx context.Context) error {
 	defer ticker.Stop()
 
 	// 立即运行一次
-	if err := s.runOnce(ctx); err != nil {
+	if _, err := s.runOnce(ctx); err != nil {
 		klog.Errorf("Initial run failed: %v", err)
 	}
+	lastRun := time.Now()
+
+	// 事件触发的循环先等待debouncePeriod合并后续事件，并保证与上次循环的间隔不小于minInterval
+	var triggerRequests <-chan struct{}
+	if s.triggers != nil {
+		triggerRequests = s.triggers.Requests()
+		klog.Infof("Event triggers enabled: debounce=%v, minInterval=%v",
+			s.config.Triggers.DebouncePeriod, s.config.Triggers.MinInterval)
+	}
+	var debounce <-chan time.Time
+
+	// 配置热加载在循环之间进行，与循环运行在同一个goroutine中
+	var configPoll <-chan time.Time
+	if s.configPath != "" && s.configPollInterval > 0 {
+		pollTicker := time.NewTicker(s.configPollInterval)
+		defer pollTicker.Stop()
+		configPoll = pollTicker.C
+		klog.Infof("Watching configuration file %s for changes every %v", s.configPath, s.configPollInterval)
+	}
 
 	for {
 		select {
@@ -79,31 +181,108 @@ func (s *Scheduler) Run(ctx context.Context) error {
 			klog.Infof("Scheduler stopped by context cancellation")
 			return ctx.Err()
 		case <-ticker.C:
-			if err := s.runOnce(ctx); err != nil {
+			// 触发的循环之后重置了定时器，这里只在minInterval大于定期间隔时生效
+			if s.triggers != nil && time.Since(lastRun) < s.config.Triggers.MinInterval {
+				klog.V(2).Infof("Skipping periodic cycle: last cycle ran %v ago, minInterval is %v",
+					time.Since(lastRun).Round(time.Second), s.config.Triggers.MinInterval)
+				continue
+			}
+			if _, err := s.runOnce(ctx); err != nil {
 				klog.Errorf("Scheduler run failed: %v", err)
 			}
+			lastRun = time.Now()
+		case <-configPoll:
+			if s.configChanged() {
+				s.reloadConfig(ticker)
+			}
+		case <-s.reloadRequests:
+			klog.Infof("Reloading configuration from %s", s.configPath)
+			s.reloadConfig(ticker)
+		case <-triggerRequests:
+			// 已经在等待中的事件会合并到同一次循环
+			if debounce == nil {
+				debounce = time.After(s.triggerDelay(lastRun, s.config.Triggers.DebouncePeriod))
+			}
+		case <-debounce:
+			// 等待期间可能已经执行过定期循环
+			if wait := s.triggerDelay(lastRun, 0); wait > 0 {
+				debounce = time.After(wait)
+				continue
+			}
+			debounce = nil
+
+			klog.Infof("Running triggered descheduling cycle: %s", strings.Join(s.triggers.TakeReasons(), "; "))
+			if _, err := s.runOnce(ctx); err != nil {
+				klog.Errorf("Triggered scheduler run failed: %v", err)
+			}
+			lastRun = time.Now()
+			// 下一次定期循环从触发的循环开始计时，避免紧接着再运行一次
+			ticker.Reset(s.config.Interval)
+		}
+	}
+}
+
+// close 停止事件广播并关闭审计记录输出目标
+func (s *Scheduler) close() {
+	s.stopEvents()
+	if s.auditSink != nil {
+		if err := s.auditSink.Close(); err != nil {
+			klog.Errorf("Failed to close audit sink: %v", err)
 		}
 	}
 }
 
-// runOnce 执行一次重调度循环
-func (s *Scheduler) runOnce(ctx context.Context) error {
+// triggerDelay 计算事件触发的循环需要等待的时间：至少等待debounce，并且距离上次循环不少于minInterval
+func (s *Scheduler) triggerDelay(lastRun time.Time, debounce time.Duration) time.Duration {
+	delay := s.config.Triggers.MinInterval - time.Since(lastRun)
+	if delay < debounce {
+		delay = debounce
+	}
+	return delay
+}
+
+// runOnce 执行一次重调度循环，策略失败不中断循环，记录在返回的结果中
+func (s *Scheduler) runOnce(ctx context.Context) (*CycleResult, error) {
 	startTime := time.Now()
 	klog.Infof("=== Starting descheduling cycle ===")
+	metrics.CyclesTotal.Inc()
+	defer func() {
+		metrics.CycleDuration.Observe(time.Since(startTime).Seconds())
+	}()
 
 	// 重置驱逐统计
 	s.evictor.ResetStats()
 
-	// 获取可用节点
-	nodes, err := s.getAvailableNodes(ctx)
+	// 刷新PDB预算
+	if err := s.evictor.RefreshPodDisruptionBudgets(ctx); err != nil {
+		return nil, fmt.Errorf("failed to refresh pod disruption budgets: %v", err)
+	}
+
+	// 生成本次循环的节点和Pod快照，所有策略共享
+	snap, err := s.cache.Snapshot()
 	if err != nil {
-		return fmt.Errorf("failed to get available nodes: %v", err)
+		return nil, fmt.Errorf("failed to build cluster snapshot: %v", err)
 	}
+	s.strategyContext.Snapshot = snap
+
+	// 循环标识用于关联同一次循环的审计记录
+	cycleID := startTime.UTC().Format("20060102T150405.000Z")
+	s.evictor.BeginCycle(cycleID, snap)
+	klog.V(2).Infof("Cycle ID: %s", cycleID)
+
+	result := &CycleResult{}
+
+	// 驱逐时间安排检查从上次循环到本次循环之间的时间
+	prevCycleStart := s.lastCycleStart
+	s.lastCycleStart = startTime
+
+	// 获取可用节点
+	nodes := s.getAvailableNodes(snap)
 
 	klog.Infof("Found %d available nodes", len(nodes))
 	if len(nodes) < 2 {
 		klog.Infof("Need at least 2 nodes for descheduling, found %d. Skipping cycle.", len(nodes))
-		return nil
+		return result, nil
 	}
 
 	// 应用节点选择器过滤
@@ -112,45 +291,85 @@ func (s *Scheduler) runOnce(ctx context.Context) error {
 
 	if len(filteredNodes) == 0 {
 		klog.Infof("No nodes match the node selector. Skipping cycle.")
-		return nil
+		return result, nil
 	}
 
-	// 执行所有启用的策略
+	// 执行所有启用的策略，不在驱逐时间安排内的策略只观察不驱逐
+	var observeOnly []string
 	for _, strategy := range s.strategies {
 		if !strategy.IsEnabled() {
 			continue
 		}
 
+		if sched, ok := s.schedules[strategy.Name()]; ok && !sched.AllowsSince(prevCycleStart, startTime) {
+			klog.Infof("Strategy %s is outside its eviction schedule %s, running in observe-only mode",
+				strategy.Name(), sched)
+			observeOnly = append(observeOnly, strategy.Name())
+			s.evictor.SetObserveOnly(strategy.Name(), true)
+			metrics.StrategyObserveOnly.WithLabelValues(strategy.Name()).Set(1)
+		} else {
+			s.evictor.SetObserveOnly(strategy.Name(), false)
+			metrics.StrategyObserveOnly.WithLabelValues(strategy.Name()).Set(0)
+		}
+
 		klog.Infof("--- Executing strategy: %s ---", strategy.Name())
+		result.Strategies = append(result.Strategies, strategy.Name())
 		strategyStartTime := time.Now()
 
 		err := strategy.Execute(ctx, filteredNodes)
+		strategyDuration := time.Since(strategyStartTime)
+		metrics.StrategyDuration.WithLabelValues(strategy.Name()).Observe(strategyDuration.Seconds())
 		if err != nil {
+			metrics.StrategyErrors.WithLabelValues(strategy.Name()).Inc()
 			klog.Errorf("Strategy %s failed: %v", strategy.Name(), err)
+			result.FailedStrategies = append(result.FailedStrategies, strategy.Name())
 			continue
 		}
 
-		strategyDuration := time.Since(strategyStartTime)
 		klog.Infof("Strategy %s completed in %v", strategy.Name(), strategyDuration)
 	}
 
 	// 输出统计信息
-	s.printCycleStats(startTime)
+	s.printCycleStats(startTime, observeOnly)
+	result.RefusedByLimits = s.evictor.GetEvictionStats().RefusedByLimits
+
+	// DryRun模式下输出汇总的驱逐计划
+	if s.config.DryRun {
+		s.writeDryRunReport()
+	}
 
 	klog.Infof("=== Descheduling cycle completed ===")
-	return nil
+	return result, nil
 }
 
-// getAvailableNodes 获取可用的节点
-func (s *Scheduler) getAvailableNodes(ctx context.Context) ([]*v1.Node, error) {
-	nodeList, err := s.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, err
+// buildSchedules 解析每个策略的驱逐时间安排，策略自身的时间安排优先于全局时间安排
+func buildSchedules(cfg *config.Config, enabledStrategies []strategies.Strategy) (map[string]*schedule.Schedule, error) {
+	schedules := make(map[string]*schedule.Schedule)
+
+	for _, strategy := range enabledStrategies {
+		scheduleConfig := strategy.Schedule()
+		if scheduleConfig == nil {
+			scheduleConfig = cfg.Schedule
+		}
+		if scheduleConfig == nil {
+			continue
+		}
+
+		sched, err := scheduleConfig.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule for strategy %s: %v", strategy.Name(), err)
+		}
+		schedules[strategy.Name()] = sched
+		klog.Infof("Strategy %s evicts only within %s", strategy.Name(), sched)
 	}
 
+	return schedules, nil
+}
+
+// getAvailableNodes 获取可用的节点
+func (s *Scheduler) getAvailableNodes(snap *snapshot.Snapshot) []*v1.Node {
 	var availableNodes []*v1.Node
-	for i := range nodeList.Items {
-		node := &nodeList.Items[i]
+	for _, node := range snap.Nodes() {
 
 		// 只考虑就绪且可调度的节点
 		if utils.IsReadyNode(node) && utils.IsSchedulableNode(node) {
@@ -162,7 +381,7 @@ func (s *Scheduler) getAvailableNodes(ctx context.Context) ([]*v1.Node, error) {
 		}
 	}
 
-	return availableNodes, nil
+	return availableNodes
 }
 
 // filterNodesBySelector 根据节点选择器过滤节点
@@ -196,7 +415,7 @@ func (s *Scheduler) nodeMatchesSelector(node *v1.Node) bool {
 }
 
 // printCycleStats 输出循环统计信息
-func (s *Scheduler) printCycleStats(startTime time.Time) {
+func (s *Scheduler) printCycleStats(startTime time.Time, observeOnly []string) {
 	duration := time.Since(startTime)
 	stats := s.evictor.GetEvictionStats()
 
@@ -204,6 +423,13 @@ func (s *Scheduler) printCycleStats(startTime time.Time) {
 	klog.Infof("Duration: %v", duration)
 	klog.Infof("Total evicted: %d", stats.TotalEvicted)
 	klog.Infof("Failed evictions: %d", stats.FailedEvictions)
+	if stats.RefusedByLimits > 0 {
+		klog.Infof("Refused by eviction limits: %d", stats.RefusedByLimits)
+	}
+
+	if len(observeOnly) > 0 {
+		klog.Infof("Strategies skipped by schedule (observe-only): %s", strings.Join(observeOnly, ", "))
+	}
 
 	if len(stats.EvictedByNode) > 0 {
 		klog.Infof("Evictions by node:")
@@ -219,6 +445,13 @@ func (s *Scheduler) printCycleStats(startTime time.Time) {
 		}
 	}
 
+	if len(stats.EvictedByStrategy) > 0 {
+		klog.Infof("Evictions by strategy:")
+		for strategyName, count := range stats.EvictedByStrategy {
+			klog.Infof("  %s: %d", strategyName, count)
+		}
+	}
+
 	if len(stats.EvictedByReason) > 0 {
 		klog.Infof("Evictions by reason:")
 		for reason, count := range stats.EvictedByReason {
@@ -227,9 +460,33 @@ func (s *Scheduler) printCycleStats(startTime time.Time) {
 	}
 }
 
+// writeDryRunReport 输出本次循环的驱逐计划报告
+func (s *Scheduler) writeDryRunReport() {
+	reportConfig := s.config.DryRunReport
+	report := s.evictor.DryRunReport()
+
+	var w io.Writer = os.Stdout
+	if reportConfig.Path != "" {
+		file, err := os.Create(reportConfig.Path)
+		if err != nil {
+			klog.Errorf("Failed to create dry-run report %s: %v", reportConfig.Path, err)
+			return
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := report.Write(w, reportConfig.Format); err != nil {
+		klog.Errorf("Failed to write dry-run report: %v", err)
+		return
+	}
+	if reportConfig.Path != "" {
+		klog.Infof("Dry-run report with %d planned evictions written to %s", report.TotalPods, reportConfig.Path)
+	}
+}
+
 // GetStats 获取调度器统计信息
 // 注意：目前主要通过printCycleStats使用，但保留此方法供外部监控系统调用
 func (s *Scheduler) GetStats() eviction.EvictionStats {
-    return s.evictor.GetEvictionStats()
+	return s.evictor.GetEvictionStats()
 }
-
@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/metrics"
+	"lightweight-descheduler/pkg/snapshot"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// 触发类型，同时用作指标标签
+const (
+	triggerNodeAdded      = "node_added"
+	triggerNodeReady      = "node_ready"
+	triggerNodeUncordoned = "node_uncordoned"
+	triggerPodFailures    = "pod_failures"
+)
+
+// triggerWatcher 监听节点和Pod的变化，在满足条件时请求一次重调度循环
+// 多个事件在调度器处理之前会合并为一次请求
+type triggerWatcher struct {
+	config *config.TriggersConfig
+
+	// requests 循环请求，容量为1，已有未处理的请求时新的请求被合并
+	requests chan struct{}
+
+	mu sync.Mutex
+	// reasons 自上次处理以来收到的触发原因
+	reasons []string
+	// podFailures 时间窗口内的Pod失败时间
+	podFailures []time.Time
+}
+
+// newTriggerWatcher 创建事件触发器并向缓存注册事件处理函数
+func newTriggerWatcher(cfg *config.TriggersConfig, podCache *snapshot.Cache) (*triggerWatcher, error) {
+	w := &triggerWatcher{
+		config:   cfg,
+		requests: make(chan struct{}, 1),
+	}
+
+	if cfg.NodeAdded || cfg.NodeReady || cfg.NodeUncordoned {
+		err := podCache.AddNodeEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc:    w.onNodeAdd,
+			UpdateFunc: w.onNodeUpdate,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch nodes: %v", err)
+		}
+	}
+
+	if cfg.PodFailureThreshold > 0 {
+		err := podCache.AddPodEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc:    w.onPodAdd,
+			UpdateFunc: w.onPodUpdate,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch pods: %v", err)
+		}
+	}
+
+	return w, nil
+}
+
+// Requests 返回循环请求通道
+func (w *triggerWatcher) Requests() <-chan struct{} {
+	return w.requests
+}
+
+// TakeReasons 返回并清空自上次调用以来的触发原因
+func (w *triggerWatcher) TakeReasons() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	reasons := w.reasons
+	w.reasons = nil
+	return reasons
+}
+
+// fire 记录触发原因并请求一次循环
+func (w *triggerWatcher) fire(trigger, reason string) {
+	klog.V(2).Infof("Descheduling triggered: %s", reason)
+	metrics.CycleTriggers.WithLabelValues(trigger).Inc()
+
+	w.mu.Lock()
+	w.reasons = append(w.reasons, reason)
+	w.mu.Unlock()
+
+	select {
+	case w.requests <- struct{}{}:
+	default:
+	}
+}
+
+// onNodeAdd 处理新节点加入，缓存首次同步时的节点不触发
+func (w *triggerWatcher) onNodeAdd(obj interface{}, isInInitialList bool) {
+	node, ok := obj.(*v1.Node)
+	if !ok || isInInitialList || !w.config.NodeAdded {
+		return
+	}
+	w.fire(triggerNodeAdded, fmt.Sprintf("node %s added", node.Name))
+}
+
+// onNodeUpdate 处理节点变为Ready和解除cordon
+func (w *triggerWatcher) onNodeUpdate(oldObj, newObj interface{}) {
+	oldNode, ok := oldObj.(*v1.Node)
+	if !ok {
+		return
+	}
+	newNode, ok := newObj.(*v1.Node)
+	if !ok {
+		return
+	}
+
+	if w.config.NodeReady && !utils.IsReadyNode(oldNode) && utils.IsReadyNode(newNode) {
+		w.fire(triggerNodeReady, fmt.Sprintf("node %s became ready", newNode.Name))
+	}
+	if w.config.NodeUncordoned && oldNode.Spec.Unschedulable && !newNode.Spec.Unschedulable {
+		w.fire(triggerNodeUncordoned, fmt.Sprintf("node %s uncordoned", newNode.Name))
+	}
+}
+
+// onPodAdd 处理新出现的失败Pod，缓存首次同步时的Pod不计入
+func (w *triggerWatcher) onPodAdd(obj interface{}, isInInitialList bool) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || isInInitialList || pod.Status.Phase != v1.PodFailed {
+		return
+	}
+	w.recordPodFailure()
+}
+
+// onPodUpdate 处理Pod进入Failed状态
+func (w *triggerWatcher) onPodUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	newPod, ok := newObj.(*v1.Pod)
+	if !ok {
+		return
+	}
+
+	if oldPod.Status.Phase != v1.PodFailed && newPod.Status.Phase == v1.PodFailed {
+		w.recordPodFailure()
+	}
+}
+
+// recordPodFailure 记录一次Pod失败，时间窗口内的失败数量达到阈值时触发循环
+func (w *triggerWatcher) recordPodFailure() {
+	now := time.Now()
+	cutoff := now.Add(-w.config.PodFailureWindow)
+
+	w.mu.Lock()
+	failures := w.podFailures[:0]
+	for _, t := range w.podFailures {
+		if t.After(cutoff) {
+			failures = append(failures, t)
+		}
+	}
+	failures = append(failures, now)
+
+	count := len(failures)
+	if count >= w.config.PodFailureThreshold {
+		failures = nil
+	}
+	w.podFailures = failures
+	w.mu.Unlock()
+
+	if count >= w.config.PodFailureThreshold {
+		w.fire(triggerPodFailures, fmt.Sprintf("%d pods failed within %v", count, w.config.PodFailureWindow))
+	}
+}
@@ -0,0 +1,134 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// nodeNameIndex Pod按所在节点名称建立的索引
+const nodeNameIndex = "spec.nodeName"
+
+// Cache 基于informer的Pod、节点和命名空间缓存
+// 通过watch保持与API Server同步，每次重调度循环从缓存生成快照，不再为每个节点单独LIST Pod
+type Cache struct {
+	factory    informers.SharedInformerFactory
+	podIndexer cache.Indexer
+	nodeLister corelisters.NodeLister
+
+	// namespaceLister 命名空间注解作为其中Pod的驱逐注解默认值
+	namespaceLister corelisters.NamespaceLister
+}
+
+// NewCache 创建Pod、节点和命名空间缓存，需要调用Start后才能使用
+func NewCache(client kubernetes.Interface) (*Cache, error) {
+	// managedFields对重调度没有用处，去掉以减少缓存占用的内存
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithTransform(stripManagedFields))
+
+	podInformer := factory.Core().V1().Pods().Informer()
+	if err := podInformer.AddIndexers(cache.Indexers{nodeNameIndex: indexByNodeName}); err != nil {
+		return nil, fmt.Errorf("failed to add pod node name index: %v", err)
+	}
+
+	return &Cache{
+		factory:         factory,
+		podIndexer:      podInformer.GetIndexer(),
+		nodeLister:      factory.Core().V1().Nodes().Lister(),
+		namespaceLister: factory.Core().V1().Namespaces().Lister(),
+	}, nil
+}
+
+// Start 启动informer并等待缓存完成首次同步
+func (c *Cache) Start(ctx context.Context) error {
+	c.factory.Start(ctx.Done())
+
+	klog.Infof("Waiting for pod, node and namespace caches to sync")
+	for informerType, synced := range c.factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync cache for %v", informerType)
+		}
+	}
+	klog.Infof("Pod, node and namespace caches synced")
+	return nil
+}
+
+// AddNodeEventHandler 注册节点事件处理函数，需在Start之前调用
+func (c *Cache) AddNodeEventHandler(handler cache.ResourceEventHandler) error {
+	_, err := c.factory.Core().V1().Nodes().Informer().AddEventHandler(handler)
+	return err
+}
+
+// AddPodEventHandler 注册Pod事件处理函数，需在Start之前调用
+func (c *Cache) AddPodEventHandler(handler cache.ResourceEventHandler) error {
+	_, err := c.factory.Core().V1().Pods().Informer().AddEventHandler(handler)
+	return err
+}
+
+// Snapshot 从缓存生成当前集群状态的快照
+func (c *Cache) Snapshot() (*Snapshot, error) {
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes from cache: %v", err)
+	}
+
+	namespaces, err := c.namespaceLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces from cache: %v", err)
+	}
+
+	snapshot := &Snapshot{
+		nodes:       nodes,
+		nodesByName: make(map[string]*v1.Node, len(nodes)),
+		podsByNode:  make(map[string][]*v1.Pod, len(nodes)),
+		namespaces:  make(map[string]*v1.Namespace, len(namespaces)),
+	}
+	for _, namespace := range namespaces {
+		snapshot.namespaces[namespace.Name] = namespace
+	}
+
+	for _, node := range nodes {
+		snapshot.nodesByName[node.Name] = node
+
+		objs, err := c.podIndexer.ByIndex(nodeNameIndex, node.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods on node %s from cache: %v", node.Name, err)
+		}
+
+		pods := make([]*v1.Pod, 0, len(objs))
+		for _, obj := range objs {
+			if pod, ok := obj.(*v1.Pod); ok {
+				pods = append(pods, pod)
+			}
+		}
+		snapshot.podsByNode[node.Name] = pods
+	}
+
+	klog.V(2).Infof("Built cluster snapshot with %d nodes and %d pods", len(nodes), snapshot.podCount())
+	return snapshot, nil
+}
+
+// stripManagedFields 去掉对象的managedFields
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
+
+// indexByNodeName 按spec.nodeName索引Pod，未调度的Pod不建立索引
+func indexByNodeName(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
@@ -0,0 +1,43 @@
+package snapshot
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// Snapshot 一次重调度循环开始时的节点、Pod和命名空间状态
+// 快照中的对象与informer缓存共享，只能读取，不能修改
+type Snapshot struct {
+	nodes       []*v1.Node
+	nodesByName map[string]*v1.Node
+	podsByNode  map[string][]*v1.Pod
+	namespaces  map[string]*v1.Namespace
+}
+
+// Nodes 返回快照中的所有节点
+func (s *Snapshot) Nodes() []*v1.Node {
+	return s.nodes
+}
+
+// Node 返回指定名称的节点，不存在时返回nil
+func (s *Snapshot) Node(name string) *v1.Node {
+	return s.nodesByName[name]
+}
+
+// PodsOnNode 返回指定节点上的所有Pod（包括已结束的Pod）
+func (s *Snapshot) PodsOnNode(nodeName string) []*v1.Pod {
+	return s.podsByNode[nodeName]
+}
+
+// Namespace 返回指定名称的命名空间，不存在时返回nil
+func (s *Snapshot) Namespace(name string) *v1.Namespace {
+	return s.namespaces[name]
+}
+
+// podCount 返回快照中的Pod总数
+func (s *Snapshot) podCount() int {
+	count := 0
+	for _, pods := range s.podsByNode {
+		count += len(pods)
+	}
+	return count
+}
@@ -0,0 +1,237 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/eviction"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// HighNodeUtilizationStrategy 高节点利用率（装箱）策略
+// 与LowNodeUtilization相反，此策略清空所有资源都低于thresholds的节点，
+// 把Pod集中到其他节点上，便于集群自动扩缩容回收空闲节点。
+// 需要配合使用MostAllocated打分的调度器，否则被驱逐的Pod可能重新分散
+type HighNodeUtilizationStrategy struct {
+	client  kubernetes.Interface
+	config  *config.HighNodeUtilizationConfig
+	context *StrategyContext
+}
+
+// nodePlacement 模拟放置时目标节点的剩余资源
+type nodePlacement struct {
+	name     string
+	capacity resourceCapacity
+}
+
+// NewHighNodeUtilizationStrategy 创建高节点利用率策略
+func NewHighNodeUtilizationStrategy(ctx *StrategyContext) *HighNodeUtilizationStrategy {
+	return &HighNodeUtilizationStrategy{
+		client:  ctx.Client,
+		config:  ctx.Config.Strategies.HighNodeUtilization,
+		context: ctx,
+	}
+}
+
+// Name 返回策略名称
+func (s *HighNodeUtilizationStrategy) Name() string {
+	return "HighNodeUtilization"
+}
+
+// IsEnabled 检查策略是否启用
+func (s *HighNodeUtilizationStrategy) IsEnabled() bool {
+	return s.config != nil && s.config.Enabled
+}
+
+// Schedule 返回策略的驱逐时间安排
+func (s *HighNodeUtilizationStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
+// Execute 执行高节点利用率策略
+func (s *HighNodeUtilizationStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
+	klog.Infof("Executing %s strategy", s.Name())
+
+	resources := utils.ThresholdResources(s.config.Thresholds)
+
+	// 计算节点利用率并区分待清空节点和目标节点
+	podsByNode := make(map[string][]*v1.Pod, len(nodes))
+	var underUtilized, others []*utils.NodeResourceUtilization
+	allResources := make(map[v1.ResourceName]bool)
+
+	for _, node := range nodes {
+		pods := s.context.Snapshot.PodsOnNode(node.Name)
+		podsByNode[node.Name] = pods
+
+		utilization := utils.CalculateNodeUtilization(node, pods)
+		for name := range utilization.Allocatable {
+			allResources[name] = true
+		}
+
+		if utils.IsNodeUnderUtilized(utilization, s.config.Thresholds) {
+			underUtilized = append(underUtilized, utilization)
+			klog.V(2).Infof("Node %s is under-utilized (%s)", node.Name, utilization.Summary(resources))
+			continue
+		}
+		others = append(others, utilization)
+	}
+
+	// 目标节点的剩余容量包含集群中出现的所有资源，节点没有的资源（如GPU）剩余容量为0
+	var targets []*nodePlacement
+	for _, utilization := range others {
+		capacity := make(resourceCapacity, len(allResources))
+		for name := range allResources {
+			capacity[name] = utilization.Allocatable[name] - utilization.Usage[name]
+		}
+		targets = append(targets, &nodePlacement{name: utilization.NodeName, capacity: capacity})
+	}
+
+	if len(underUtilized) == 0 {
+		klog.Infof("No under-utilized nodes found, skipping %s", s.Name())
+		return nil
+	}
+	if len(targets) == 0 {
+		klog.Infof("No nodes above thresholds to receive pods, skipping %s", s.Name())
+		return nil
+	}
+
+	// 利用率最低的节点最容易清空，优先处理
+	sort.SliceStable(underUtilized, func(i, j int) bool {
+		return totalPercent(underUtilized[i], resources) < totalPercent(underUtilized[j], resources)
+	})
+
+	emptiedCount := 0
+	evictedCount := 0
+	skippedCount := 0
+
+	for _, nodeUtil := range underUtilized {
+		if s.config.MaxNodesToEmptyPerCycle > 0 && emptiedCount >= s.config.MaxNodesToEmptyPerCycle {
+			klog.V(2).Infof("Reached maxNodesToEmptyPerCycle (%d), stopping", s.config.MaxNodesToEmptyPerCycle)
+			break
+		}
+
+		pods, reason := s.getPodsToMove(podsByNode[nodeUtil.NodeName])
+		if reason != "" {
+			klog.V(2).Infof("Skipping node %s: %s", nodeUtil.NodeName, reason)
+			skippedCount++
+			continue
+		}
+		if len(pods) == 0 {
+			continue
+		}
+
+		// 驱逐限制或PDB预算不足以驱逐全部Pod时不处理该节点，部分清空的节点无法被回收
+		if ok, reason := s.context.Evictor.CanEvictAll(pods, s.Name()); !ok {
+			klog.V(2).Infof("Skipping node %s: %s", nodeUtil.NodeName, reason)
+			skippedCount++
+			continue
+		}
+
+		// 所有Pod都能在其他节点放下时才清空该节点，避免驱逐后无处调度
+		placements, ok := s.simulatePlacement(pods, targets)
+		if !ok {
+			klog.V(2).Infof("Skipping node %s: its pods do not fit on the remaining nodes", nodeUtil.NodeName)
+			skippedCount++
+			continue
+		}
+
+		klog.V(2).Infof("Emptying node %s (%d pods)", nodeUtil.NodeName, len(pods))
+		emptiedCount++
+
+		for _, pod := range pods {
+			reason := fmt.Sprintf("Node under-utilized - consolidating pods off node %s (%s)",
+				nodeUtil.NodeName, nodeUtil.Summary(resources))
+			err := s.context.Evictor.EvictPod(ctx, pod, s.Name(), reason)
+			if err != nil {
+				klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+				continue
+			}
+
+			// 扣除目标节点上为此Pod预留的容量
+			placements[utils.PodKey(pod)].capacity.consume(utils.PodRequests(pod))
+
+			evictedCount++
+			klog.V(2).Infof("Successfully evicted pod %s/%s from under-utilized node %s",
+				pod.Namespace, pod.Name, nodeUtil.NodeName)
+		}
+	}
+
+	klog.Infof("HighNodeUtilization strategy completed. Emptied nodes: %d, Evicted: %d, Skipped nodes: %d",
+		emptiedCount, evictedCount, skippedCount)
+	return nil
+}
+
+// getPodsToMove 获取清空节点需要驱逐的Pod
+// DaemonSet和静态Pod不会阻止节点被回收，直接忽略；其他任何不可驱逐的Pod都会使节点无法清空，此时返回原因
+func (s *HighNodeUtilizationStrategy) getPodsToMove(pods []*v1.Pod) ([]*v1.Pod, string) {
+	var toMove []*v1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if eviction.IsDaemonSetPod(pod) || eviction.IsStaticPod(pod) {
+			continue
+		}
+
+		if canEvict, reason := s.context.Evictor.CanEvictPod(pod, s.Name()); !canEvict {
+			return nil, fmt.Sprintf("pod %s cannot be evicted: %s", utils.PodKey(pod), reason)
+		}
+		toMove = append(toMove, pod)
+	}
+	return toMove, ""
+}
+
+// simulatePlacement 按请求从大到小把Pod依次放入第一个放得下的目标节点
+// 全部放得下时返回每个Pod对应的目标节点，但不修改目标节点的剩余容量
+func (s *HighNodeUtilizationStrategy) simulatePlacement(pods []*v1.Pod, targets []*nodePlacement) (map[string]*nodePlacement, bool) {
+	sorted := make([]*v1.Pod, len(pods))
+	copy(sorted, pods)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		cpuI, memoryI := utils.GetPodRequests(sorted[i])
+		cpuJ, memoryJ := utils.GetPodRequests(sorted[j])
+		if cpuI != cpuJ {
+			return cpuI > cpuJ
+		}
+		return memoryI > memoryJ
+	})
+
+	// 在副本上模拟，只有全部放得下时调用方才会真正扣除容量
+	simulated := make([]resourceCapacity, len(targets))
+	for i, target := range targets {
+		simulated[i] = target.capacity.clone()
+	}
+
+	placements := make(map[string]*nodePlacement, len(pods))
+	for _, pod := range sorted {
+		requests := utils.PodRequests(pod)
+		placed := false
+		for i, target := range targets {
+			if simulated[i].fits(requests) {
+				simulated[i].consume(requests)
+				placements[utils.PodKey(pod)] = target
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			return nil, false
+		}
+	}
+
+	return placements, true
+}
+
+// totalPercent 返回节点在指定资源上的使用率之和
+func totalPercent(utilization *utils.NodeResourceUtilization, resources []v1.ResourceName) int {
+	total := 0
+	for _, name := range resources {
+		total += utilization.Percent[name]
+	}
+	return total
+}
@@ -0,0 +1,155 @@
+package strategies
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/eviction"
+	"lightweight-descheduler/pkg/snapshot"
+)
+
+func newTestNode(name, cpu, memory string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+				v1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+}
+
+func newTestPod(name, nodeName, app, cpu, memory string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"app": app},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       app,
+				UID:        types.UID("rs-" + app),
+			}},
+		},
+		Spec: v1.PodSpec{
+			NodeName: nodeName,
+			Containers: []v1.Container{{
+				Name: "app",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse(cpu),
+						v1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func newTestPDB(app string, disruptionsAllowed int32) *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(1)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: app, Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+// runHighNodeUtilization 在由objects组成的集群上以DryRun模式执行一次HighNodeUtilization，返回驱逐统计
+func runHighNodeUtilization(t *testing.T, objects ...runtime.Object) eviction.EvictionStats {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewSimpleClientset(objects...)
+	cfg := &config.Config{
+		DryRun:         true,
+		EvictionPolicy: &config.EvictionPolicyConfig{},
+		Strategies: config.StrategiesConfig{
+			HighNodeUtilization: &config.HighNodeUtilizationConfig{
+				Enabled:    true,
+				Thresholds: config.ResourceThresholds{"cpu": 20, "memory": 20, "pods": 20},
+			},
+		},
+	}
+
+	podCache, err := snapshot.NewCache(client)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	if err := podCache.Start(ctx); err != nil {
+		t.Fatalf("failed to start cache: %v", err)
+	}
+	snap, err := podCache.Snapshot()
+	if err != nil {
+		t.Fatalf("failed to build snapshot: %v", err)
+	}
+
+	evictor := eviction.NewDefaultPodEvictor(client, cfg, nil, nil)
+	if err := evictor.RefreshPodDisruptionBudgets(ctx); err != nil {
+		t.Fatalf("failed to load pod disruption budgets: %v", err)
+	}
+	evictor.BeginCycle("test", snap)
+
+	strategy := NewHighNodeUtilizationStrategy(&StrategyContext{
+		Client:   client,
+		Config:   cfg,
+		Evictor:  evictor,
+		Snapshot: snap,
+	})
+	if err := strategy.Execute(ctx, snap.Nodes()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	return evictor.GetEvictionStats()
+}
+
+// newConsolidationCluster 返回一个利用率很低的节点（运行两个app=web的Pod）和一个能容纳这些Pod的繁忙节点
+func newConsolidationCluster() []runtime.Object {
+	return []runtime.Object{
+		newTestNode("idle", "4", "8Gi"),
+		newTestNode("busy", "4", "8Gi"),
+		newTestPod("web-1", "idle", "web", "100m", "128Mi"),
+		newTestPod("web-2", "idle", "web", "100m", "128Mi"),
+		newTestPod("batch", "busy", "batch", "3", "6Gi"),
+	}
+}
+
+func TestHighNodeUtilizationSkipsNodeWhenPDBCannotCoverAllPods(t *testing.T) {
+	objects := append(newConsolidationCluster(), newTestPDB("web", 1))
+
+	stats := runHighNodeUtilization(t, objects...)
+	if stats.TotalEvicted != 0 {
+		t.Errorf("evicted %d pods, want the node left untouched because the PDB allows only 1 disruption",
+			stats.TotalEvicted)
+	}
+}
+
+func TestHighNodeUtilizationEmptiesNodeWithinPDB(t *testing.T) {
+	objects := append(newConsolidationCluster(), newTestPDB("web", 2))
+
+	stats := runHighNodeUtilization(t, objects...)
+	if got := stats.EvictedByNode["idle"]; got != 2 {
+		t.Errorf("evicted %d pods from node idle, want 2", got)
+	}
+	if got := stats.EvictedByNode["busy"]; got != 0 {
+		t.Errorf("evicted %d pods from node busy, want 0", got)
+	}
+}
@@ -3,13 +3,15 @@ package strategies
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
 	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utilization"
 	"lightweight-descheduler/pkg/utils"
 )
 
@@ -18,14 +20,27 @@ type LowNodeUtilizationStrategy struct {
 	client  kubernetes.Interface
 	config  *config.LowNodeUtilizationConfig
 	context *StrategyContext
+
+	// source 利用率数据来源，节点分类和Pod排序都使用同一来源
+	source    utilization.Source
+	sourceErr error
+
+	// resources 阈值中配置的资源，用于日志和驱逐原因
+	resources []v1.ResourceName
 }
 
 // NewLowNodeUtilizationStrategy 创建低节点利用率策略
 func NewLowNodeUtilizationStrategy(ctx *StrategyContext) *LowNodeUtilizationStrategy {
+	cfg := ctx.Config.Strategies.LowNodeUtilization
+	source, err := utilization.NewSource(cfg.UtilizationSource, ctx.MetricsClient)
+
 	return &LowNodeUtilizationStrategy{
-		client:  ctx.Client,
-		config:  ctx.Config.Strategies.LowNodeUtilization,
-		context: ctx,
+		client:    ctx.Client,
+		config:    cfg,
+		context:   ctx,
+		source:    source,
+		sourceErr: err,
+		resources: utils.ThresholdResources(cfg.TargetThresholds),
 	}
 }
 
@@ -39,10 +54,19 @@ func (s *LowNodeUtilizationStrategy) IsEnabled() bool {
 	return s.config != nil && s.config.Enabled
 }
 
+// Schedule 返回策略的驱逐时间安排
+func (s *LowNodeUtilizationStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
 // Execute 执行低节点利用率策略
 func (s *LowNodeUtilizationStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
 	klog.Infof("Executing %s strategy", s.Name())
 
+	if s.sourceErr != nil {
+		return fmt.Errorf("invalid utilization source: %v", s.sourceErr)
+	}
+
 	// 过滤出就绪且可调度的节点
 	readyNodes := utils.FilterReadySchedulableNodes(nodes)
 	if len(readyNodes) < 2 {
@@ -50,12 +74,14 @@ func (s *LowNodeUtilizationStrategy) Execute(ctx context.Context, nodes []*v1.No
 		return nil
 	}
 
-	// 计算每个节点的资源利用率
-	nodeUtilizations, err := s.calculateNodeUtilizations(ctx, readyNodes)
-	if err != nil {
-		return fmt.Errorf("failed to calculate node utilizations: %v", err)
+	// 刷新利用率数据
+	if err := s.source.Refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh %s utilization: %v", s.source.Name(), err)
 	}
 
+	// 计算每个节点的资源利用率
+	nodeUtilizations := s.calculateNodeUtilizations(readyNodes)
+
 	// 分类节点：低利用率、高利用率、正常利用率
 	lowUtilizationNodes, overUtilizationNodes := s.categorizeNodes(nodeUtilizations)
 
@@ -79,42 +105,23 @@ func (s *LowNodeUtilizationStrategy) Execute(ctx context.Context, nodes []*v1.No
 }
 
 // calculateNodeUtilizations 计算节点资源利用率
-func (s *LowNodeUtilizationStrategy) calculateNodeUtilizations(ctx context.Context, nodes []*v1.Node) (map[string]*utils.NodeResourceUtilization, error) {
+func (s *LowNodeUtilizationStrategy) calculateNodeUtilizations(nodes []*v1.Node) map[string]*utils.NodeResourceUtilization {
 	utilizations := make(map[string]*utils.NodeResourceUtilization)
 
 	for _, node := range nodes {
-		// 获取节点上的Pod
-		pods, err := s.getPodsOnNode(ctx, node.Name)
+		// 计算利用率
+		nodeUtil, err := s.source.NodeUtilization(node, s.context.Snapshot.PodsOnNode(node.Name))
 		if err != nil {
-			return nil, fmt.Errorf("failed to get pods on node %s: %v", node.Name, err)
+			klog.Warningf("Skipping node %s: %v", node.Name, err)
+			continue
 		}
+		utilizations[node.Name] = nodeUtil
 
-		// 计算利用率
-		utilization := utils.CalculateNodeUtilization(node, pods)
-		utilizations[node.Name] = utilization
-
-		klog.V(2).Infof("Node %s utilization: CPU=%d%%, Memory=%d%%, Pods=%d%%",
-			node.Name, utilization.CPUPercent, utilization.MemoryPercent, utilization.PodsPercent)
-	}
-
-	return utilizations, nil
-}
-
-// getPodsOnNode 获取指定节点上的Pod
-func (s *LowNodeUtilizationStrategy) getPodsOnNode(ctx context.Context, nodeName string) ([]*v1.Pod, error) {
-	podList, err := s.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var pods []*v1.Pod
-	for i := range podList.Items {
-		pods = append(pods, &podList.Items[i])
+		klog.V(2).Infof("Node %s utilization (%s): %s",
+			node.Name, s.source.Name(), nodeUtil.Summary(s.resources))
 	}
 
-	return pods, nil
+	return utilizations
 }
 
 // categorizeNodes 分类节点
@@ -122,23 +129,11 @@ func (s *LowNodeUtilizationStrategy) categorizeNodes(utilizations map[string]*ut
 	lowUtilization []*utils.NodeResourceUtilization,
 	overUtilization []*utils.NodeResourceUtilization) {
 
-	// 转换配置为map格式
-	thresholds := map[string]int{
-		"cpu":    s.config.Thresholds.CPU,
-		"memory": s.config.Thresholds.Memory,
-		"pods":   s.config.Thresholds.Pods,
-	}
-	targetThresholds := map[string]int{
-		"cpu":    s.config.TargetThresholds.CPU,
-		"memory": s.config.TargetThresholds.Memory,
-		"pods":   s.config.TargetThresholds.Pods,
-	}
-
 	for _, utilization := range utilizations {
-		if utils.IsNodeUnderUtilized(utilization, thresholds) {
+		if utils.IsNodeUnderUtilized(utilization, s.config.Thresholds) {
 			lowUtilization = append(lowUtilization, utilization)
 			klog.V(2).Infof("Node %s is under-utilized", utilization.NodeName)
-		} else if utils.IsNodeOverUtilized(utilization, targetThresholds) {
+		} else if utils.IsNodeOverUtilized(utilization, s.config.TargetThresholds) {
 			overUtilization = append(overUtilization, utilization)
 			klog.V(2).Infof("Node %s is over-utilized", utilization.NodeName)
 		}
@@ -151,58 +146,81 @@ func (s *LowNodeUtilizationStrategy) categorizeNodes(utilizations map[string]*ut
 func (s *LowNodeUtilizationStrategy) evictPodsFromOverUtilizedNodes(
 	ctx context.Context,
 	overUtilizedNodes []*utils.NodeResourceUtilization,
-	_ []*utils.NodeResourceUtilization) error {
+	lowUtilizedNodes []*utils.NodeResourceUtilization) error {
 
 	evictedCount := 0
 	skippedCount := 0
 
+	// 计算低利用率节点在不超过Thresholds的前提下还能接收的资源
+	capacity := s.calculateAvailableCapacity(lowUtilizedNodes)
+	klog.V(2).Infof("Available capacity on under-utilized nodes: %s", formatResources(capacity))
+	initial := capacity.clone()
+
 	for _, nodeUtil := range overUtilizedNodes {
-		klog.V(2).Infof("Processing over-utilized node: %s (CPU=%d%%, Memory=%d%%, Pods=%d%%)",
-			nodeUtil.NodeName, nodeUtil.CPUPercent, nodeUtil.MemoryPercent, nodeUtil.PodsPercent)
+		if capacity.exhausted(initial) {
+			klog.V(2).Infof("Under-utilized nodes have no capacity left, stopping evictions")
+			break
+		}
+
+		klog.V(2).Infof("Processing over-utilized node: %s (%s)", nodeUtil.NodeName, nodeUtil.Summary(s.resources))
 
 		// 获取可驱逐的Pod
-		evictablePods, err := s.getEvictablePodsOnNode(ctx, nodeUtil.NodeName)
-		if err != nil {
-			klog.Errorf("Failed to get evictable pods on node %s: %v", nodeUtil.NodeName, err)
-			continue
-		}
+		evictablePods := s.getEvictablePodsOnNode(nodeUtil.NodeName)
 
 		// 按优先级排序Pod，优先驱逐低优先级的Pod
 		sortedPods := s.sortPodsByPriority(evictablePods)
 
-		// 驱逐Pod，但限制数量避免过度驱逐
-		maxEvictions := s.calculateMaxEvictions(nodeUtil)
+		// 驱逐原因使用节点处理前的利用率
+		evictionReason := fmt.Sprintf("Node over-utilization balancing - %s", nodeUtil.Summary(s.resources))
 		evicted := 0
 
 		for _, pod := range sortedPods {
-			if evicted >= maxEvictions {
+			// 节点已经回落到目标阈值以下，不再驱逐
+			if !utils.IsNodeOverUtilized(nodeUtil, s.config.TargetThresholds) {
 				break
 			}
+			if capacity.exhausted(initial) {
+				break
+			}
+
+			// 检查低利用率节点是否还能容纳此Pod
+			usage := s.source.PodUsage(pod)
+			if !capacity.fits(usage) {
+				klog.V(3).Infof("Skipping pod %s/%s: %s (%s) do not fit into remaining capacity",
+					pod.Namespace, pod.Name, s.source.Name(), formatResources(usage))
+				s.context.Evictor.RecordSkipped(pod, s.Name(), fmt.Sprintf("%s (%s) do not fit into remaining capacity",
+					s.source.Name(), formatResources(usage)))
+				skippedCount++
+				continue
+			}
 
 			// 检查是否可以驱逐此Pod
-			if canEvict, reason := s.context.Evictor.CanEvictPod(pod); !canEvict {
+			if canEvict, reason := s.context.Evictor.CanEvictPod(pod, s.Name()); !canEvict {
 				klog.V(3).Infof("Skipping pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+				s.context.Evictor.RecordSkipped(pod, s.Name(), reason)
 				skippedCount++
 				continue
 			}
 
 			// 驱逐Pod
-			evictionReason := fmt.Sprintf("Node over-utilization balancing - CPU=%d%%, Memory=%d%%, Pods=%d%%",
-				nodeUtil.CPUPercent, nodeUtil.MemoryPercent, nodeUtil.PodsPercent)
-
-			err := s.context.Evictor.EvictPod(ctx, pod, evictionReason)
+			err := s.context.Evictor.EvictPod(ctx, pod, s.Name(), evictionReason)
 			if err != nil {
 				klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
 				continue
 			}
 
+			// 更新剩余容量和节点利用率
+			capacity.consume(usage)
+			nodeUtil.RemovePod(usage)
+
 			evicted++
 			evictedCount++
 			klog.V(2).Infof("Successfully evicted pod %s/%s from over-utilized node %s",
 				pod.Namespace, pod.Name, nodeUtil.NodeName)
 		}
 
-		klog.V(2).Infof("Evicted %d pods from node %s", evicted, nodeUtil.NodeName)
+		klog.V(2).Infof("Evicted %d pods from node %s, utilization now %s",
+			evicted, nodeUtil.NodeName, nodeUtil.Summary(s.resources))
 	}
 
 	klog.Infof("LowNodeUtilization strategy completed. Evicted: %d, Skipped: %d",
@@ -210,26 +228,109 @@ func (s *LowNodeUtilizationStrategy) evictPodsFromOverUtilizedNodes(
 	return nil
 }
 
-// getEvictablePodsOnNode 获取节点上可驱逐的Pod
-func (s *LowNodeUtilizationStrategy) getEvictablePodsOnNode(ctx context.Context, nodeName string) ([]*v1.Pod, error) {
-	pods, err := s.getPodsOnNode(ctx, nodeName)
-	if err != nil {
-		return nil, err
+// resourceCapacity 按资源名称索引的剩余容量，单位与utils.QuantityValue一致，pods为剩余Pod数量
+type resourceCapacity map[v1.ResourceName]int64
+
+// exhausted 检查是否有任一资源的容量已被用完。
+// 初始容量不为正的资源（例如低利用率节点上没有的扩展资源）不计入，
+// 需要这些资源的Pod由fits逐个拒绝，不影响只请求其他资源的Pod
+func (c resourceCapacity) exhausted(initial resourceCapacity) bool {
+	for name, remaining := range c {
+		if initial[name] > 0 && remaining <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// fits 检查Pod用量是否能放入剩余容量，只检查容量中包含的资源
+func (c resourceCapacity) fits(usage map[v1.ResourceName]int64) bool {
+	for name, remaining := range c {
+		needed := usage[name]
+		if name == v1.ResourcePods {
+			needed = 1
+		}
+		if needed > remaining {
+			return false
+		}
+	}
+	return true
+}
+
+// consume 从剩余容量中扣除Pod用量
+func (c resourceCapacity) consume(usage map[v1.ResourceName]int64) {
+	for name := range c {
+		if name == v1.ResourcePods {
+			c[name]--
+			continue
+		}
+		c[name] -= usage[name]
+	}
+}
+
+// clone 复制剩余容量
+func (c resourceCapacity) clone() resourceCapacity {
+	cloned := make(resourceCapacity, len(c))
+	for name, remaining := range c {
+		cloned[name] = remaining
+	}
+	return cloned
+}
+
+// calculateAvailableCapacity 计算低利用率节点在Thresholds以内的剩余容量总和
+func (s *LowNodeUtilizationStrategy) calculateAvailableCapacity(lowUtilizedNodes []*utils.NodeResourceUtilization) resourceCapacity {
+	capacity := make(resourceCapacity)
+
+	for name, threshold := range s.config.Thresholds {
+		resourceName := v1.ResourceName(name)
+		for _, nodeUtil := range lowUtilizedNodes {
+			limit := nodeUtil.Allocatable[resourceName] * int64(threshold) / 100
+			capacity[resourceName] += max64(0, limit-nodeUtil.Usage[resourceName])
+		}
 	}
 
+	return capacity
+}
+
+// formatResources 格式化资源用量用于日志，按资源名称排序
+func formatResources(resources map[v1.ResourceName]int64) string {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := resources[v1.ResourceName(name)]
+		switch {
+		case name == string(v1.ResourceCPU):
+			parts = append(parts, fmt.Sprintf("%s=%s", name, utils.FormatCPU(value)))
+		case name == string(v1.ResourceMemory) || name == string(v1.ResourceEphemeralStorage) ||
+			strings.HasPrefix(name, v1.ResourceHugePagesPrefix):
+			parts = append(parts, fmt.Sprintf("%s=%s", name, utils.FormatBytes(value)))
+		default:
+			parts = append(parts, fmt.Sprintf("%s=%d", name, value))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// getEvictablePodsOnNode 获取节点上可驱逐的Pod
+func (s *LowNodeUtilizationStrategy) getEvictablePodsOnNode(nodeName string) []*v1.Pod {
 	var evictablePods []*v1.Pod
-	for _, pod := range pods {
+	for _, pod := range s.context.Snapshot.PodsOnNode(nodeName) {
 		// 跳过系统Pod和特殊状态的Pod
 		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
 			continue
 		}
 
-		if canEvict, _ := s.context.Evictor.CanEvictPod(pod); canEvict {
+		if canEvict, _ := s.context.Evictor.CanEvictPod(pod, s.Name()); canEvict {
 			evictablePods = append(evictablePods, pod)
 		}
 	}
 
-	return evictablePods, nil
+	return evictablePods
 }
 
 // sortPodsByPriority 按优先级排序Pod
@@ -245,39 +346,31 @@ func (s *LowNodeUtilizationStrategy) sortPodsByPriority(pods []*v1.Pod) []*v1.Po
 		}
 	}
 
+	// 同一优先级内按利用率来源给出的用量从大到小排序，尽快降低节点利用率
+	s.sortPodsByUsage(lowPriorityPods)
+	s.sortPodsByUsage(normalPods)
+
 	// 先返回低优先级的Pod
 	result := append(lowPriorityPods, normalPods...)
 	return result
 }
 
-// calculateMaxEvictions 计算节点的最大驱逐数量
-func (s *LowNodeUtilizationStrategy) calculateMaxEvictions(nodeUtil *utils.NodeResourceUtilization) int {
-	// 简单策略：根据超出阈值的程度计算驱逐数量
-	cpuExcess := max(0, nodeUtil.CPUPercent-s.config.TargetThresholds.CPU)
-	memoryExcess := max(0, nodeUtil.MemoryPercent-s.config.TargetThresholds.Memory)
-	podsExcess := max(0, nodeUtil.PodsPercent-s.config.TargetThresholds.Pods)
-
-	// 取最大的超出比例，转换为驱逐Pod数量
-	maxExcess := max(cpuExcess, max(memoryExcess, podsExcess))
-
-	// 基于超出比例计算驱逐数量，最少1个，最多5个
-	evictions := max(1, min(5, maxExcess/10))
-
-	return evictions
+// sortPodsByUsage 按CPU用量（相同时按内存用量）从大到小排序
+func (s *LowNodeUtilizationStrategy) sortPodsByUsage(pods []*v1.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		usageI := s.source.PodUsage(pods[i])
+		usageJ := s.source.PodUsage(pods[j])
+		if usageI[v1.ResourceCPU] != usageJ[v1.ResourceCPU] {
+			return usageI[v1.ResourceCPU] > usageJ[v1.ResourceCPU]
+		}
+		return usageI[v1.ResourceMemory] > usageJ[v1.ResourceMemory]
+	})
 }
 
-// max 返回两个整数的最大值
-func max(a, b int) int {
+// max64 返回两个int64的最大值
+func max64(a, b int64) int64 {
 	if a > b {
 		return a
 	}
 	return b
 }
-
-// min 返回两个整数的最小值
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
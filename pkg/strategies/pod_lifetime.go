@@ -0,0 +1,152 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// PodLifeTimeStrategy 长时间运行Pod回收策略
+// 驱逐存活时间超过maxPodLifeTimeSeconds的Pod，最旧的优先，
+// 配合limits中的驱逐限制把回收分散到多个循环中
+type PodLifeTimeStrategy struct {
+	client  kubernetes.Interface
+	config  *config.PodLifeTimeConfig
+	context *StrategyContext
+
+	selector    labels.Selector
+	selectorErr error
+}
+
+// NewPodLifeTimeStrategy 创建长时间运行Pod回收策略
+func NewPodLifeTimeStrategy(ctx *StrategyContext) *PodLifeTimeStrategy {
+	cfg := ctx.Config.Strategies.PodLifeTime
+	selector, err := labels.Parse(cfg.LabelSelector)
+
+	return &PodLifeTimeStrategy{
+		client:      ctx.Client,
+		config:      cfg,
+		context:     ctx,
+		selector:    selector,
+		selectorErr: err,
+	}
+}
+
+// Name 返回策略名称
+func (s *PodLifeTimeStrategy) Name() string {
+	return "PodLifeTime"
+}
+
+// IsEnabled 检查策略是否启用
+func (s *PodLifeTimeStrategy) IsEnabled() bool {
+	return s.config != nil && s.config.Enabled
+}
+
+// Schedule 返回策略的驱逐时间安排
+func (s *PodLifeTimeStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
+// Execute 执行长时间运行Pod回收策略
+func (s *PodLifeTimeStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
+	klog.Infof("Executing %s strategy", s.Name())
+
+	if s.selectorErr != nil {
+		return fmt.Errorf("invalid label selector: %v", s.selectorErr)
+	}
+
+	maxLifeTime := time.Duration(s.config.MaxPodLifeTimeSeconds) * time.Second
+
+	// 收集所有节点上超过最大存活时间的Pod
+	var expiredPods []*v1.Pod
+	for _, node := range nodes {
+		pods := s.context.Snapshot.PodsOnNode(node.Name)
+
+		for _, pod := range pods {
+			if !s.shouldProcessPod(pod) {
+				continue
+			}
+			if time.Since(pod.CreationTimestamp.Time) > maxLifeTime {
+				expiredPods = append(expiredPods, pod)
+			}
+		}
+	}
+
+	klog.V(2).Infof("Found %d pods older than %v", len(expiredPods), maxLifeTime)
+
+	// 最旧的Pod优先驱逐
+	sort.SliceStable(expiredPods, func(i, j int) bool {
+		return expiredPods[i].CreationTimestamp.Before(&expiredPods[j].CreationTimestamp)
+	})
+
+	evictedCount := 0
+	skippedCount := 0
+
+	for _, pod := range expiredPods {
+		// 检查是否可以驱逐此Pod
+		if canEvict, reason := s.context.Evictor.CanEvictPod(pod, s.Name()); !canEvict {
+			klog.V(3).Infof("Skipping pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+			s.context.Evictor.RecordSkipped(pod, s.Name(), reason)
+			skippedCount++
+			continue
+		}
+
+		// 驱逐Pod
+		age := time.Since(pod.CreationTimestamp.Time).Round(time.Second)
+		reason := fmt.Sprintf("Pod lifetime exceeded - age %v exceeds max lifetime %v", age, maxLifeTime)
+		err := s.context.Evictor.EvictPod(ctx, pod, s.Name(), reason)
+		if err != nil {
+			klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+
+		evictedCount++
+		klog.V(2).Infof("Successfully evicted pod %s/%s (age %v) on node %s",
+			pod.Namespace, pod.Name, age, pod.Spec.NodeName)
+	}
+
+	klog.Infof("PodLifeTime strategy completed. Evicted: %d, Skipped: %d",
+		evictedCount, skippedCount)
+	return nil
+}
+
+// shouldProcessPod 检查是否应该处理此Pod
+func (s *PodLifeTimeStrategy) shouldProcessPod(pod *v1.Pod) bool {
+	if !namespaceAllowed(pod.Namespace, s.config.IncludedNamespaces, s.config.ExcludedNamespaces) {
+		return false
+	}
+
+	if !s.selector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+
+	if len(s.config.PodStatuses) > 0 && !s.matchesPodStatus(pod) {
+		return false
+	}
+
+	return true
+}
+
+// matchesPodStatus 检查Pod的phase或容器等待原因是否在podStatuses中
+func (s *PodLifeTimeStrategy) matchesPodStatus(pod *v1.Pod) bool {
+	if utils.Contains(s.config.PodStatuses, string(pod.Status.Phase)) {
+		return true
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && utils.Contains(s.config.PodStatuses, status.State.Waiting.Reason) {
+			return true
+		}
+	}
+
+	return false
+}
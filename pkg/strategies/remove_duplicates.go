@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
@@ -39,6 +38,11 @@ func (s *RemoveDuplicatesStrategy) IsEnabled() bool {
 	return s.config != nil && s.config.Enabled
 }
 
+// Schedule 返回策略的驱逐时间安排
+func (s *RemoveDuplicatesStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
 // Execute 执行重复Pod清理策略
 func (s *RemoveDuplicatesStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
 	klog.Infof("Executing %s strategy", s.Name())
@@ -47,10 +51,7 @@ func (s *RemoveDuplicatesStrategy) Execute(ctx context.Context, nodes []*v1.Node
 	skippedCount := 0
 
 	// 收集所有节点上的Pod信息，按签名分组
-	podGroups, err := s.groupPodsBySignature(ctx, nodes)
-	if err != nil {
-		return fmt.Errorf("failed to group pods by signature: %v", err)
-	}
+	podGroups := s.groupPodsBySignature(nodes)
 
 	klog.V(2).Infof("Found %d unique pod signatures", len(podGroups))
 
@@ -83,13 +84,14 @@ func (s *RemoveDuplicatesStrategy) Execute(ctx context.Context, nodes []*v1.Node
 				// 检查是否可以驱逐此Pod
 				if canEvict, reason := s.canEvictPod(pod); !canEvict {
 					klog.V(3).Infof("Skipping duplicate pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+					s.context.Evictor.RecordSkipped(pod, s.Name(), reason)
 					skippedCount++
 					continue
 				}
 
 				// 驱逐Pod
 				evictionReason := fmt.Sprintf("Duplicate pod removal - keeping oldest pod on node %s", nodeName)
-				err := s.context.Evictor.EvictPod(ctx, pod, evictionReason)
+				err := s.context.Evictor.EvictPod(ctx, pod, s.Name(), evictionReason)
 				if err != nil {
 					klog.Errorf("Failed to evict duplicate pod %s/%s: %v", pod.Namespace, pod.Name, err)
 					continue
@@ -108,7 +110,7 @@ func (s *RemoveDuplicatesStrategy) Execute(ctx context.Context, nodes []*v1.Node
 }
 
 // groupPodsBySignature 按Pod签名分组
-func (s *RemoveDuplicatesStrategy) groupPodsBySignature(ctx context.Context, nodes []*v1.Node) (map[string]map[string][]*v1.Pod, error) {
+func (s *RemoveDuplicatesStrategy) groupPodsBySignature(nodes []*v1.Node) map[string]map[string][]*v1.Pod {
 	// podGroups[signature][nodeName] = []*v1.Pod
 	podGroups := make(map[string]map[string][]*v1.Pod)
 
@@ -116,10 +118,7 @@ func (s *RemoveDuplicatesStrategy) groupPodsBySignature(ctx context.Context, nod
 		klog.V(2).Infof("Processing node: %s", node.Name)
 
 		// 获取节点上的Pod
-		pods, err := s.getProcessablePods(ctx, node.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get pods on node %s: %v", node.Name, err)
-		}
+		pods := s.getProcessablePods(node.Name)
 
 		// 为每个Pod生成签名并分组
 		for _, pod := range pods {
@@ -133,21 +132,13 @@ func (s *RemoveDuplicatesStrategy) groupPodsBySignature(ctx context.Context, nod
 		}
 	}
 
-	return podGroups, nil
+	return podGroups
 }
 
 // getProcessablePods 获取节点上可处理的Pod
-func (s *RemoveDuplicatesStrategy) getProcessablePods(ctx context.Context, nodeName string) ([]*v1.Pod, error) {
-	podList, err := s.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
-	})
-	if err != nil {
-		return nil, err
-	}
-
+func (s *RemoveDuplicatesStrategy) getProcessablePods(nodeName string) []*v1.Pod {
 	var processablePods []*v1.Pod
-	for i := range podList.Items {
-		pod := &podList.Items[i]
+	for _, pod := range s.context.Snapshot.PodsOnNode(nodeName) {
 
 		// 只处理正在运行的Pod
 		if pod.Status.Phase != v1.PodRunning {
@@ -167,7 +158,7 @@ func (s *RemoveDuplicatesStrategy) getProcessablePods(ctx context.Context, nodeN
 		processablePods = append(processablePods, pod)
 	}
 
-	return processablePods, nil
+	return processablePods
 }
 
 // shouldProcessNamespace 检查是否应该处理此命名空间
@@ -261,5 +252,5 @@ func (s *RemoveDuplicatesStrategy) sortPodsByAge(pods []*v1.Pod) []*v1.Pod {
 // canEvictPod 检查是否可以驱逐Pod
 func (s *RemoveDuplicatesStrategy) canEvictPod(pod *v1.Pod) (bool, string) {
 	// 使用通用的驱逐检查
-	return s.context.Evictor.CanEvictPod(pod)
+	return s.context.Evictor.CanEvictPod(pod, s.Name())
 }
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
@@ -40,6 +39,11 @@ func (s *RemoveFailedPodsStrategy) IsEnabled() bool {
 	return s.config != nil && s.config.Enabled
 }
 
+// Schedule 返回策略的驱逐时间安排
+func (s *RemoveFailedPodsStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
 // Execute 执行失败Pod清理策略
 func (s *RemoveFailedPodsStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
 	klog.Infof("Executing %s strategy", s.Name())
@@ -51,11 +55,7 @@ func (s *RemoveFailedPodsStrategy) Execute(ctx context.Context, nodes []*v1.Node
 		klog.V(2).Infof("Processing node: %s", node.Name)
 
 		// 获取节点上的所有失败Pod
-		failedPods, err := s.getFailedPods(ctx, node.Name)
-		if err != nil {
-			klog.Errorf("Failed to get failed pods on node %s: %v", node.Name, err)
-			continue
-		}
+		failedPods := s.getFailedPods(node.Name)
 
 		klog.V(2).Infof("Found %d failed pods on node %s", len(failedPods), node.Name)
 
@@ -64,6 +64,7 @@ func (s *RemoveFailedPodsStrategy) Execute(ctx context.Context, nodes []*v1.Node
 			// 检查是否可以驱逐此Pod
 			if canEvict, reason := s.canEvictPod(pod); !canEvict {
 				klog.V(3).Infof("Skipping pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+				s.context.Evictor.RecordSkipped(pod, s.Name(), reason)
 				skippedCount++
 				continue
 			}
@@ -81,7 +82,7 @@ func (s *RemoveFailedPodsStrategy) Execute(ctx context.Context, nodes []*v1.Node
 				reason += fmt.Sprintf(", Reason: %s", pod.Status.Reason)
 			}
 
-			err := s.context.Evictor.EvictPod(ctx, pod, reason)
+			err := s.context.Evictor.EvictPod(ctx, pod, s.Name(), reason)
 			if err != nil {
 				klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
 				continue
@@ -99,32 +100,22 @@ func (s *RemoveFailedPodsStrategy) Execute(ctx context.Context, nodes []*v1.Node
 }
 
 // getFailedPods 获取指定节点上的失败Pod
-func (s *RemoveFailedPodsStrategy) getFailedPods(ctx context.Context, nodeName string) ([]*v1.Pod, error) {
-	// 获取所有Pod
-	pods, err := s.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
-	})
-	if err != nil {
-		return nil, err
-	}
-
+func (s *RemoveFailedPodsStrategy) getFailedPods(nodeName string) []*v1.Pod {
 	var failedPods []*v1.Pod
-	for i := range pods.Items {
-		pod := &pods.Items[i]
-
+	for _, pod := range s.context.Snapshot.PodsOnNode(nodeName) {
 		// 只处理失败状态的Pod
 		if pod.Status.Phase == v1.PodFailed {
 			failedPods = append(failedPods, pod)
 		}
 	}
 
-	return failedPods, nil
+	return failedPods
 }
 
 // canEvictPod 检查是否可以驱逐Pod
 func (s *RemoveFailedPodsStrategy) canEvictPod(pod *v1.Pod) (bool, string) {
 	// 使用通用的驱逐检查
-	return s.context.Evictor.CanEvictPod(pod)
+	return s.context.Evictor.CanEvictPod(pod, s.Name())
 }
 
 // shouldEvictPod 检查Pod是否满足驱逐条件
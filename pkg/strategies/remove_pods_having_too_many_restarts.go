@@ -0,0 +1,170 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+)
+
+// RemovePodsHavingTooManyRestartsStrategy 重启次数过多的Pod清理策略
+// 处于CrashLoopBackOff的Pod通常仍是Running phase，RemoveFailedPods不会处理它们，
+// 此策略按容器重启次数之和驱逐这些Pod，让它们有机会被调度到其他节点
+type RemovePodsHavingTooManyRestartsStrategy struct {
+	client  kubernetes.Interface
+	config  *config.RemovePodsHavingTooManyRestartsConfig
+	context *StrategyContext
+}
+
+// NewRemovePodsHavingTooManyRestartsStrategy 创建重启次数过多的Pod清理策略
+func NewRemovePodsHavingTooManyRestartsStrategy(ctx *StrategyContext) *RemovePodsHavingTooManyRestartsStrategy {
+	return &RemovePodsHavingTooManyRestartsStrategy{
+		client:  ctx.Client,
+		config:  ctx.Config.Strategies.RemovePodsHavingTooManyRestarts,
+		context: ctx,
+	}
+}
+
+// Name 返回策略名称
+func (s *RemovePodsHavingTooManyRestartsStrategy) Name() string {
+	return "RemovePodsHavingTooManyRestarts"
+}
+
+// IsEnabled 检查策略是否启用
+func (s *RemovePodsHavingTooManyRestartsStrategy) IsEnabled() bool {
+	return s.config != nil && s.config.Enabled
+}
+
+// Schedule 返回策略的驱逐时间安排
+func (s *RemovePodsHavingTooManyRestartsStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
+// Execute 执行重启次数过多的Pod清理策略
+func (s *RemovePodsHavingTooManyRestartsStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
+	klog.Infof("Executing %s strategy", s.Name())
+
+	// 收集所有节点上的Pod，并按控制器分组用于判断其他节点上的Pod是否也在频繁重启
+	var candidates []*v1.Pod
+	podsByController := make(map[types.UID][]*v1.Pod)
+
+	for _, node := range nodes {
+		pods := s.context.Snapshot.PodsOnNode(node.Name)
+
+		for _, pod := range pods {
+			if pod.Status.Phase != v1.PodRunning && pod.Status.Phase != v1.PodPending {
+				continue
+			}
+
+			if controller := metav1.GetControllerOf(pod); controller != nil {
+				podsByController[controller.UID] = append(podsByController[controller.UID], pod)
+			}
+
+			if s.shouldProcessPod(pod) && s.restartCount(pod) >= s.config.PodRestartThreshold {
+				candidates = append(candidates, pod)
+			}
+		}
+	}
+
+	klog.V(2).Infof("Found %d pods with at least %d restarts", len(candidates), s.config.PodRestartThreshold)
+
+	evictedCount := 0
+	skippedCount := 0
+
+	for _, pod := range candidates {
+		restarts := s.restartCount(pod)
+
+		if s.config.SkipIfRestartingOnOtherNodes {
+			if other := s.findRestartingSiblingOnOtherNode(pod, podsByController); other != nil {
+				klog.V(3).Infof("Skipping pod %s/%s: pod %s/%s of the same controller on node %s is also restarting",
+					pod.Namespace, pod.Name, other.Namespace, other.Name, other.Spec.NodeName)
+				s.context.Evictor.RecordSkipped(pod, s.Name(), fmt.Sprintf("pod %s/%s of the same controller on node %s is also restarting",
+					other.Namespace, other.Name, other.Spec.NodeName))
+				skippedCount++
+				continue
+			}
+		}
+
+		// 检查是否可以驱逐此Pod
+		if canEvict, reason := s.context.Evictor.CanEvictPod(pod, s.Name()); !canEvict {
+			klog.V(3).Infof("Skipping pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+			s.context.Evictor.RecordSkipped(pod, s.Name(), reason)
+			skippedCount++
+			continue
+		}
+
+		// 驱逐Pod
+		reason := fmt.Sprintf("Too many restarts - %d restarts reached threshold %d",
+			restarts, s.config.PodRestartThreshold)
+		err := s.context.Evictor.EvictPod(ctx, pod, s.Name(), reason)
+		if err != nil {
+			klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+
+		evictedCount++
+		klog.V(2).Infof("Successfully evicted pod %s/%s with %d restarts on node %s",
+			pod.Namespace, pod.Name, restarts, pod.Spec.NodeName)
+	}
+
+	klog.Infof("RemovePodsHavingTooManyRestarts strategy completed. Evicted: %d, Skipped: %d",
+		evictedCount, skippedCount)
+	return nil
+}
+
+// restartCount 计算Pod中容器重启次数之和
+func (s *RemovePodsHavingTooManyRestartsStrategy) restartCount(pod *v1.Pod) int {
+	restarts := 0
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += int(status.RestartCount)
+	}
+
+	if s.config.IncludingInitContainers {
+		for _, status := range pod.Status.InitContainerStatuses {
+			restarts += int(status.RestartCount)
+		}
+	}
+
+	return restarts
+}
+
+// findRestartingSiblingOnOtherNode 查找同一控制器在其他节点上同样达到重启阈值的Pod
+// Pod不会在节点之间迁移，重启次数总是发生在当前节点上，因此只能通过同一控制器的其他Pod判断问题是否与节点有关
+func (s *RemovePodsHavingTooManyRestartsStrategy) findRestartingSiblingOnOtherNode(
+	pod *v1.Pod, podsByController map[types.UID][]*v1.Pod) *v1.Pod {
+
+	controller := metav1.GetControllerOf(pod)
+	if controller == nil {
+		return nil
+	}
+
+	for _, sibling := range podsByController[controller.UID] {
+		if sibling.Spec.NodeName == pod.Spec.NodeName {
+			continue
+		}
+		if s.restartCount(sibling) >= s.config.PodRestartThreshold {
+			return sibling
+		}
+	}
+
+	return nil
+}
+
+// shouldProcessPod 检查是否应该处理此Pod
+func (s *RemovePodsHavingTooManyRestartsStrategy) shouldProcessPod(pod *v1.Pod) bool {
+	if !namespaceAllowed(pod.Namespace, s.config.IncludedNamespaces, s.config.ExcludedNamespaces) {
+		return false
+	}
+
+	if hasExcludedOwnerKind(pod, s.config.ExcludeOwnerKinds) {
+		return false
+	}
+
+	return true
+}
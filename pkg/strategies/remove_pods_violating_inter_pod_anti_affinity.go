@@ -0,0 +1,264 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// RemovePodsViolatingInterPodAntiAffinityStrategy 违反Pod间反亲和性的Pod清理策略
+// 调度后标签发生变化等情况下，带有required反亲和性的Pod可能与被排斥的Pod处于同一拓扑域，
+// 此策略找出这样的Pod对，驱逐其中优先级更低（或优先级相同但更年轻）的一个
+type RemovePodsViolatingInterPodAntiAffinityStrategy struct {
+	client  kubernetes.Interface
+	config  *config.RemovePodsViolatingInterPodAntiAffinityConfig
+	context *StrategyContext
+}
+
+// NewRemovePodsViolatingInterPodAntiAffinityStrategy 创建违反Pod间反亲和性的Pod清理策略
+func NewRemovePodsViolatingInterPodAntiAffinityStrategy(ctx *StrategyContext) *RemovePodsViolatingInterPodAntiAffinityStrategy {
+	return &RemovePodsViolatingInterPodAntiAffinityStrategy{
+		client:  ctx.Client,
+		config:  ctx.Config.Strategies.RemovePodsViolatingInterPodAntiAffinity,
+		context: ctx,
+	}
+}
+
+// Name 返回策略名称
+func (s *RemovePodsViolatingInterPodAntiAffinityStrategy) Name() string {
+	return "RemovePodsViolatingInterPodAntiAffinity"
+}
+
+// IsEnabled 检查策略是否启用
+func (s *RemovePodsViolatingInterPodAntiAffinityStrategy) IsEnabled() bool {
+	return s.config != nil && s.config.Enabled
+}
+
+// Schedule 返回策略的驱逐时间安排
+func (s *RemovePodsViolatingInterPodAntiAffinityStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
+// Execute 执行违反Pod间反亲和性的Pod清理策略
+func (s *RemovePodsViolatingInterPodAntiAffinityStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
+	klog.Infof("Executing %s strategy", s.Name())
+
+	nodesByName := make(map[string]*v1.Node, len(nodes))
+	var pods []*v1.Pod
+	for _, node := range nodes {
+		nodesByName[node.Name] = node
+
+		nodePods := s.context.Snapshot.PodsOnNode(node.Name)
+		for _, pod := range nodePods {
+			if pod.Status.Phase == v1.PodRunning {
+				pods = append(pods, pod)
+			}
+		}
+	}
+
+	// 每个Pod的反亲和性条件只解析一次，候选Pod按拓扑域分组，只与同一拓扑域内的Pod比较
+	var antiAffinityPods []*antiAffinityPod
+	topologyKeys := make(map[string]bool)
+	for _, pod := range pods {
+		terms := parseAntiAffinityTerms(pod)
+		if len(terms) == 0 {
+			continue
+		}
+		antiAffinityPods = append(antiAffinityPods, &antiAffinityPod{pod: pod, terms: terms})
+		for _, term := range terms {
+			topologyKeys[term.topologyKey] = true
+		}
+	}
+	podsByDomain := groupPodsByTopologyDomain(pods, topologyKeys, nodesByName)
+
+	evictedCount := 0
+	skippedCount := 0
+	evicted := make(map[string]bool)
+
+	for _, candidate := range antiAffinityPods {
+		pod := candidate.pod
+		podNode := nodesByName[pod.Spec.NodeName]
+		if podNode == nil {
+			continue
+		}
+
+		// 同一对Pod可能违反多条条件，只处理一次
+		checked := make(map[string]bool)
+
+		for _, term := range candidate.terms {
+			if evicted[utils.PodKey(pod)] {
+				break
+			}
+
+			domain, ok := podNode.Labels[term.topologyKey]
+			if !ok {
+				continue
+			}
+
+			for _, other := range podsByDomain[term.topologyKey][domain] {
+				otherKey := utils.PodKey(other)
+				if other == pod || evicted[otherKey] || checked[otherKey] {
+					continue
+				}
+				if evicted[utils.PodKey(pod)] {
+					break
+				}
+				if !term.matches(pod, other, s.namespaceLabels(other.Namespace)) {
+					continue
+				}
+				checked[otherKey] = true
+
+				// 驱逐两者中优先级更低或更年轻的Pod
+				victim, survivor := other, pod
+				if isLessImportant(pod, other) {
+					victim, survivor = pod, other
+				}
+
+				klog.V(2).Infof("Pods %s and %s violate required anti-affinity of %s on topology key %s",
+					utils.PodKey(pod), otherKey, utils.PodKey(pod), term.topologyKey)
+
+				if !namespaceAllowed(victim.Namespace, s.config.IncludedNamespaces, s.config.ExcludedNamespaces) {
+					klog.V(3).Infof("Skipping pod %s: namespace is not processed by this strategy", utils.PodKey(victim))
+					s.context.Evictor.RecordSkipped(victim, s.Name(), "namespace is not processed by this strategy")
+					skippedCount++
+					continue
+				}
+
+				// 检查是否可以驱逐此Pod
+				if canEvict, reason := s.context.Evictor.CanEvictPod(victim, s.Name()); !canEvict {
+					klog.V(3).Infof("Skipping pod %s: %s", utils.PodKey(victim), reason)
+					s.context.Evictor.RecordSkipped(victim, s.Name(), reason)
+					skippedCount++
+					continue
+				}
+
+				reason := fmt.Sprintf("Inter-pod anti-affinity violation - co-located with %s on %s=%s",
+					utils.PodKey(survivor), term.topologyKey, domain)
+				if err := s.context.Evictor.EvictPod(ctx, victim, s.Name(), reason); err != nil {
+					klog.Errorf("Failed to evict pod %s: %v", utils.PodKey(victim), err)
+					continue
+				}
+
+				// 被驱逐的Pod参与的其他违规对都随之消除
+				evicted[utils.PodKey(victim)] = true
+				evictedCount++
+				klog.V(2).Infof("Successfully evicted pod %s violating inter-pod anti-affinity", utils.PodKey(victim))
+			}
+		}
+	}
+
+	klog.Infof("RemovePodsViolatingInterPodAntiAffinity strategy completed. Evicted: %d, Skipped: %d",
+		evictedCount, skippedCount)
+	return nil
+}
+
+// antiAffinityPod 带有required反亲和性条件的Pod及其解析后的条件
+type antiAffinityPod struct {
+	pod   *v1.Pod
+	terms []*antiAffinityTerm
+}
+
+// antiAffinityTerm 解析后的反亲和性条件
+type antiAffinityTerm struct {
+	topologyKey string
+	selector    labels.Selector
+
+	// namespaces和namespaceSelector取并集，两者都为空时只作用于Pod自身的命名空间
+	namespaces        []string
+	namespaceSelector labels.Selector
+}
+
+// requiredAntiAffinityTerms 返回Pod的required反亲和性条件
+func requiredAntiAffinityTerms(pod *v1.Pod) []v1.PodAffinityTerm {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.PodAntiAffinity == nil {
+		return nil
+	}
+	return pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+}
+
+// parseAntiAffinityTerms 解析Pod的required反亲和性条件，忽略无效的条件
+func parseAntiAffinityTerms(pod *v1.Pod) []*antiAffinityTerm {
+	var parsed []*antiAffinityTerm
+	for _, term := range requiredAntiAffinityTerms(pod) {
+		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+		if err != nil {
+			klog.V(3).Infof("Ignoring invalid anti-affinity term on pod %s: %v", utils.PodKey(pod), err)
+			continue
+		}
+
+		var namespaceSelector labels.Selector
+		if term.NamespaceSelector != nil {
+			namespaceSelector, err = metav1.LabelSelectorAsSelector(term.NamespaceSelector)
+			if err != nil {
+				klog.V(3).Infof("Ignoring invalid anti-affinity namespace selector on pod %s: %v", utils.PodKey(pod), err)
+				continue
+			}
+		}
+
+		parsed = append(parsed, &antiAffinityTerm{
+			topologyKey:       term.TopologyKey,
+			selector:          selector,
+			namespaces:        term.Namespaces,
+			namespaceSelector: namespaceSelector,
+		})
+	}
+	return parsed
+}
+
+// groupPodsByTopologyDomain 按拓扑键和拓扑域分组Pod，所在节点没有该拓扑键的Pod不属于任何拓扑域
+func groupPodsByTopologyDomain(pods []*v1.Pod, topologyKeys map[string]bool, nodesByName map[string]*v1.Node) map[string]map[string][]*v1.Pod {
+	podsByDomain := make(map[string]map[string][]*v1.Pod, len(topologyKeys))
+	for key := range topologyKeys {
+		podsByDomain[key] = make(map[string][]*v1.Pod)
+	}
+
+	for _, pod := range pods {
+		node := nodesByName[pod.Spec.NodeName]
+		if node == nil {
+			continue
+		}
+		for key := range topologyKeys {
+			if domain, ok := node.Labels[key]; ok {
+				podsByDomain[key][domain] = append(podsByDomain[key][domain], pod)
+			}
+		}
+	}
+
+	return podsByDomain
+}
+
+// matches 检查同一拓扑域内的other是否违反pod的这条反亲和性条件
+func (t *antiAffinityTerm) matches(pod, other *v1.Pod, otherNamespaceLabels labels.Set) bool {
+	return t.matchesNamespace(pod, other.Namespace, otherNamespaceLabels) &&
+		t.selector.Matches(labels.Set(other.Labels))
+}
+
+// matchesNamespace 检查反亲和性条件是否作用于指定命名空间，与kube-scheduler一致
+func (t *antiAffinityTerm) matchesNamespace(pod *v1.Pod, namespace string, namespaceLabels labels.Set) bool {
+	if len(t.namespaces) == 0 && t.namespaceSelector == nil {
+		return namespace == pod.Namespace
+	}
+
+	if utils.Contains(t.namespaces, namespace) {
+		return true
+	}
+
+	return t.namespaceSelector != nil && t.namespaceSelector.Matches(namespaceLabels)
+}
+
+// namespaceLabels 从快照中获取命名空间的标签
+func (s *RemovePodsViolatingInterPodAntiAffinityStrategy) namespaceLabels(name string) labels.Set {
+	namespace := s.context.Snapshot.Namespace(name)
+	if namespace == nil {
+		return nil
+	}
+	return labels.Set(namespace.Labels)
+}
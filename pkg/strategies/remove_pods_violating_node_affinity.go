@@ -0,0 +1,155 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+)
+
+// RemovePodsViolatingNodeAffinityStrategy 违反节点亲和性的Pod清理策略
+// 节点标签变化后，按requiredDuringSchedulingIgnoredDuringExecution亲和性或nodeSelector
+// 调度的Pod不会被自动迁移，此策略驱逐这些Pod，让调度器把它们放到满足条件的节点上
+type RemovePodsViolatingNodeAffinityStrategy struct {
+	client  kubernetes.Interface
+	config  *config.RemovePodsViolatingNodeAffinityConfig
+	context *StrategyContext
+}
+
+// NewRemovePodsViolatingNodeAffinityStrategy 创建违反节点亲和性的Pod清理策略
+func NewRemovePodsViolatingNodeAffinityStrategy(ctx *StrategyContext) *RemovePodsViolatingNodeAffinityStrategy {
+	return &RemovePodsViolatingNodeAffinityStrategy{
+		client:  ctx.Client,
+		config:  ctx.Config.Strategies.RemovePodsViolatingNodeAffinity,
+		context: ctx,
+	}
+}
+
+// Name 返回策略名称
+func (s *RemovePodsViolatingNodeAffinityStrategy) Name() string {
+	return "RemovePodsViolatingNodeAffinity"
+}
+
+// IsEnabled 检查策略是否启用
+func (s *RemovePodsViolatingNodeAffinityStrategy) IsEnabled() bool {
+	return s.config != nil && s.config.Enabled
+}
+
+// Schedule 返回策略的驱逐时间安排
+func (s *RemovePodsViolatingNodeAffinityStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
+// Execute 执行违反节点亲和性的Pod清理策略
+func (s *RemovePodsViolatingNodeAffinityStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
+	klog.Infof("Executing %s strategy", s.Name())
+
+	evictedCount := 0
+	skippedCount := 0
+
+	for _, node := range nodes {
+		klog.V(2).Infof("Processing node: %s", node.Name)
+
+		pods := s.context.Snapshot.PodsOnNode(node.Name)
+
+		for _, pod := range pods {
+			if !s.shouldProcessPod(pod) {
+				continue
+			}
+
+			// 检查Pod当前所在节点是否仍满足其亲和性要求
+			affinity := nodeaffinity.GetRequiredNodeAffinity(pod)
+			matches, err := affinity.Match(node)
+			if err != nil {
+				klog.V(3).Infof("Skipping pod %s/%s: invalid node affinity: %v", pod.Namespace, pod.Name, err)
+				s.context.Evictor.RecordSkipped(pod, s.Name(), fmt.Sprintf("invalid node affinity: %v", err))
+				skippedCount++
+				continue
+			}
+			if matches {
+				continue
+			}
+
+			klog.V(2).Infof("Pod %s/%s no longer matches the node affinity of node %s",
+				pod.Namespace, pod.Name, node.Name)
+
+			// 确保至少还有一个其他节点能满足此Pod，否则驱逐后Pod只会处于Pending状态
+			if !s.hasOtherFittingNode(affinity, node, nodes) {
+				klog.V(3).Infof("Skipping pod %s/%s: no other node satisfies its node affinity",
+					pod.Namespace, pod.Name)
+				s.context.Evictor.RecordSkipped(pod, s.Name(), "no other node satisfies its node affinity")
+				skippedCount++
+				continue
+			}
+
+			// 检查是否可以驱逐此Pod
+			if canEvict, reason := s.context.Evictor.CanEvictPod(pod, s.Name()); !canEvict {
+				klog.V(3).Infof("Skipping pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+				s.context.Evictor.RecordSkipped(pod, s.Name(), reason)
+				skippedCount++
+				continue
+			}
+
+			// 驱逐Pod
+			reason := fmt.Sprintf("Node affinity violation - node %s no longer satisfies required node affinity or nodeSelector", node.Name)
+			err = s.context.Evictor.EvictPod(ctx, pod, s.Name(), reason)
+			if err != nil {
+				klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+				continue
+			}
+
+			evictedCount++
+			klog.V(2).Infof("Successfully evicted pod %s/%s violating node affinity on node %s",
+				pod.Namespace, pod.Name, node.Name)
+		}
+	}
+
+	klog.Infof("RemovePodsViolatingNodeAffinity strategy completed. Evicted: %d, Skipped: %d",
+		evictedCount, skippedCount)
+	return nil
+}
+
+// shouldProcessPod 检查是否应该处理此Pod
+func (s *RemovePodsViolatingNodeAffinityStrategy) shouldProcessPod(pod *v1.Pod) bool {
+	// 只处理正在运行的Pod
+	if pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+
+	// 没有nodeSelector和required节点亲和性的Pod不可能违反约束
+	if len(pod.Spec.NodeSelector) == 0 &&
+		(pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+			pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil) {
+		return false
+	}
+
+	if !namespaceAllowed(pod.Namespace, s.config.IncludedNamespaces, s.config.ExcludedNamespaces) {
+		return false
+	}
+
+	if hasExcludedOwnerKind(pod, s.config.ExcludeOwnerKinds) {
+		return false
+	}
+
+	return true
+}
+
+// hasOtherFittingNode 检查除当前节点外是否有节点满足Pod的节点亲和性
+func (s *RemovePodsViolatingNodeAffinityStrategy) hasOtherFittingNode(
+	affinity nodeaffinity.RequiredNodeAffinity, current *v1.Node, nodes []*v1.Node) bool {
+
+	for _, node := range nodes {
+		if node.Name == current.Name {
+			continue
+		}
+		if matches, err := affinity.Match(node); err == nil && matches {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// RemovePodsViolatingNodeTaintsStrategy 不容忍节点污点的Pod清理策略
+// 节点新增NoSchedule污点后，已在其上运行且不容忍该污点的Pod不会被自动驱逐，
+// 此策略驱逐这些Pod
+type RemovePodsViolatingNodeTaintsStrategy struct {
+	client  kubernetes.Interface
+	config  *config.RemovePodsViolatingNodeTaintsConfig
+	context *StrategyContext
+}
+
+// NewRemovePodsViolatingNodeTaintsStrategy 创建不容忍节点污点的Pod清理策略
+func NewRemovePodsViolatingNodeTaintsStrategy(ctx *StrategyContext) *RemovePodsViolatingNodeTaintsStrategy {
+	return &RemovePodsViolatingNodeTaintsStrategy{
+		client:  ctx.Client,
+		config:  ctx.Config.Strategies.RemovePodsViolatingNodeTaints,
+		context: ctx,
+	}
+}
+
+// Name 返回策略名称
+func (s *RemovePodsViolatingNodeTaintsStrategy) Name() string {
+	return "RemovePodsViolatingNodeTaints"
+}
+
+// IsEnabled 检查策略是否启用
+func (s *RemovePodsViolatingNodeTaintsStrategy) IsEnabled() bool {
+	return s.config != nil && s.config.Enabled
+}
+
+// Schedule 返回策略的驱逐时间安排
+func (s *RemovePodsViolatingNodeTaintsStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
+// Execute 执行不容忍节点污点的Pod清理策略
+func (s *RemovePodsViolatingNodeTaintsStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
+	klog.Infof("Executing %s strategy", s.Name())
+
+	evictedCount := 0
+	skippedCount := 0
+
+	for _, node := range nodes {
+		// 节点上没有需要处理的污点时跳过
+		if !s.hasRelevantTaints(node) {
+			continue
+		}
+
+		klog.V(2).Infof("Processing tainted node: %s", node.Name)
+
+		pods := s.context.Snapshot.PodsOnNode(node.Name)
+
+		for _, pod := range pods {
+			if !s.shouldProcessPod(pod) {
+				continue
+			}
+
+			// 查找Pod不容忍的污点
+			taint, untolerated := corev1helpers.FindMatchingUntoleratedTaint(
+				node.Spec.Taints, pod.Spec.Tolerations, s.isRelevantTaint)
+			if !untolerated {
+				continue
+			}
+
+			// 检查是否可以驱逐此Pod
+			if canEvict, reason := s.context.Evictor.CanEvictPod(pod, s.Name()); !canEvict {
+				klog.V(3).Infof("Skipping pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+				s.context.Evictor.RecordSkipped(pod, s.Name(), reason)
+				skippedCount++
+				continue
+			}
+
+			// 驱逐Pod
+			reason := fmt.Sprintf("Node taint violation - pod does not tolerate taint %s on node %s",
+				taint.ToString(), node.Name)
+			err := s.context.Evictor.EvictPod(ctx, pod, s.Name(), reason)
+			if err != nil {
+				klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+				continue
+			}
+
+			evictedCount++
+			klog.V(2).Infof("Successfully evicted pod %s/%s not tolerating taint %s on node %s",
+				pod.Namespace, pod.Name, taint.ToString(), node.Name)
+		}
+	}
+
+	klog.Infof("RemovePodsViolatingNodeTaints strategy completed. Evicted: %d, Skipped: %d",
+		evictedCount, skippedCount)
+	return nil
+}
+
+// isRelevantTaint 检查污点是否在本策略的处理范围内
+func (s *RemovePodsViolatingNodeTaintsStrategy) isRelevantTaint(taint *v1.Taint) bool {
+	switch taint.Effect {
+	case v1.TaintEffectNoSchedule:
+	case v1.TaintEffectPreferNoSchedule:
+		if !s.config.IncludePreferNoSchedule {
+			return false
+		}
+	default:
+		// NoExecute污点由kubelet的污点管理器负责驱逐
+		return false
+	}
+
+	if len(s.config.IncludedTaintKeys) > 0 {
+		return utils.Contains(s.config.IncludedTaintKeys, taint.Key)
+	}
+	return true
+}
+
+// hasRelevantTaints 检查节点是否有需要处理的污点
+func (s *RemovePodsViolatingNodeTaintsStrategy) hasRelevantTaints(node *v1.Node) bool {
+	for i := range node.Spec.Taints {
+		if s.isRelevantTaint(&node.Spec.Taints[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldProcessPod 检查是否应该处理此Pod
+func (s *RemovePodsViolatingNodeTaintsStrategy) shouldProcessPod(pod *v1.Pod) bool {
+	// 只处理正在运行的Pod
+	if pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+
+	if !namespaceAllowed(pod.Namespace, s.config.IncludedNamespaces, s.config.ExcludedNamespaces) {
+		return false
+	}
+
+	if hasExcludedOwnerKind(pod, s.config.ExcludeOwnerKinds) {
+		return false
+	}
+
+	return true
+}
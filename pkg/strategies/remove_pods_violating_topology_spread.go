@@ -0,0 +1,267 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// RemovePodsViolatingTopologySpreadConstraintsStrategy 违反拓扑分布约束的Pod清理策略
+// 节点扩容等事件之后，Pod在各拓扑域之间的分布可能严重倾斜，此策略按每个约束计算各域的Pod数量，
+// 从Pod最多的域驱逐尽量少的Pod，使最大和最小域之间的差值回到maxSkew以内
+type RemovePodsViolatingTopologySpreadConstraintsStrategy struct {
+	client  kubernetes.Interface
+	config  *config.RemovePodsViolatingTopologySpreadConstraintsConfig
+	context *StrategyContext
+}
+
+// topologyConstraint 去重后的拓扑分布约束，作用于同一命名空间内匹配selector的Pod
+type topologyConstraint struct {
+	namespace   string
+	topologyKey string
+	maxSkew     int
+	selector    labels.Selector
+	description string
+}
+
+// topologyDomain 单个拓扑域的状态
+type topologyDomain struct {
+	name  string
+	count int
+
+	// candidates 域中可驱逐的Pod，按驱逐优先顺序排列
+	candidates []*v1.Pod
+}
+
+// NewRemovePodsViolatingTopologySpreadConstraintsStrategy 创建违反拓扑分布约束的Pod清理策略
+func NewRemovePodsViolatingTopologySpreadConstraintsStrategy(ctx *StrategyContext) *RemovePodsViolatingTopologySpreadConstraintsStrategy {
+	return &RemovePodsViolatingTopologySpreadConstraintsStrategy{
+		client:  ctx.Client,
+		config:  ctx.Config.Strategies.RemovePodsViolatingTopologySpreadConstraints,
+		context: ctx,
+	}
+}
+
+// Name 返回策略名称
+func (s *RemovePodsViolatingTopologySpreadConstraintsStrategy) Name() string {
+	return "RemovePodsViolatingTopologySpreadConstraints"
+}
+
+// IsEnabled 检查策略是否启用
+func (s *RemovePodsViolatingTopologySpreadConstraintsStrategy) IsEnabled() bool {
+	return s.config != nil && s.config.Enabled
+}
+
+// Schedule 返回策略的驱逐时间安排
+func (s *RemovePodsViolatingTopologySpreadConstraintsStrategy) Schedule() *config.ScheduleConfig {
+	return s.config.Schedule
+}
+
+// Execute 执行违反拓扑分布约束的Pod清理策略
+func (s *RemovePodsViolatingTopologySpreadConstraintsStrategy) Execute(ctx context.Context, nodes []*v1.Node) error {
+	klog.Infof("Executing %s strategy", s.Name())
+
+	// 收集所有节点上运行中的Pod
+	podsByNamespace := make(map[string][]*v1.Pod)
+	for _, node := range nodes {
+		pods := s.context.Snapshot.PodsOnNode(node.Name)
+		for _, pod := range pods {
+			if pod.Status.Phase != v1.PodRunning {
+				continue
+			}
+			podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+		}
+	}
+
+	constraints := s.collectConstraints(podsByNamespace)
+	klog.V(2).Infof("Found %d distinct topology spread constraints", len(constraints))
+
+	// 按约束选出需要驱逐的Pod，同一个Pod只驱逐一次
+	selected := make(map[string]bool)
+	var toEvict []*v1.Pod
+	var reasons []string
+
+	for _, constraint := range constraints {
+		for _, pod := range s.balanceConstraint(constraint, nodes, podsByNamespace[constraint.namespace], selected) {
+			selected[utils.PodKey(pod)] = true
+			toEvict = append(toEvict, pod)
+			reasons = append(reasons, fmt.Sprintf("Topology spread constraint violation - %s", constraint.description))
+		}
+	}
+
+	evictedCount := 0
+	skippedCount := 0
+	for i, pod := range toEvict {
+		// 驱逐前再次检查，前面的驱逐可能已经消耗了PDB预算
+		if canEvict, reason := s.context.Evictor.CanEvictPod(pod, s.Name()); !canEvict {
+			klog.V(3).Infof("Skipping pod %s/%s: %s", pod.Namespace, pod.Name, reason)
+			s.context.Evictor.RecordSkipped(pod, s.Name(), reason)
+			skippedCount++
+			continue
+		}
+
+		err := s.context.Evictor.EvictPod(ctx, pod, s.Name(), reasons[i])
+		if err != nil {
+			klog.Errorf("Failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+
+		evictedCount++
+		klog.V(2).Infof("Successfully evicted pod %s/%s from node %s to rebalance topology spread",
+			pod.Namespace, pod.Name, pod.Spec.NodeName)
+	}
+
+	klog.Infof("RemovePodsViolatingTopologySpreadConstraints strategy completed. Evicted: %d, Skipped: %d",
+		evictedCount, skippedCount)
+	return nil
+}
+
+// collectConstraints 收集并去重所有Pod上的拓扑分布约束
+func (s *RemovePodsViolatingTopologySpreadConstraintsStrategy) collectConstraints(podsByNamespace map[string][]*v1.Pod) []*topologyConstraint {
+	var constraints []*topologyConstraint
+	seen := make(map[string]bool)
+
+	for namespace, pods := range podsByNamespace {
+		if !namespaceAllowed(namespace, s.config.IncludedNamespaces, s.config.ExcludedNamespaces) {
+			continue
+		}
+
+		for _, pod := range pods {
+			for _, tsc := range pod.Spec.TopologySpreadConstraints {
+				if tsc.WhenUnsatisfiable == v1.ScheduleAnyway && !s.config.IncludeSoftConstraints {
+					continue
+				}
+
+				selector, err := metav1.LabelSelectorAsSelector(tsc.LabelSelector)
+				if err != nil {
+					klog.V(3).Infof("Ignoring invalid topology spread constraint on pod %s/%s: %v",
+						pod.Namespace, pod.Name, err)
+					continue
+				}
+
+				key := fmt.Sprintf("%s|%s|%d|%s", namespace, tsc.TopologyKey, tsc.MaxSkew, selector.String())
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				constraints = append(constraints, &topologyConstraint{
+					namespace:   namespace,
+					topologyKey: tsc.TopologyKey,
+					maxSkew:     int(tsc.MaxSkew),
+					selector:    selector,
+					description: fmt.Sprintf("%s skew exceeds maxSkew %d for pods matching %q",
+						tsc.TopologyKey, tsc.MaxSkew, selector.String()),
+				})
+			}
+		}
+	}
+
+	// 保证处理顺序稳定
+	sort.Slice(constraints, func(i, j int) bool {
+		return constraints[i].description+constraints[i].namespace < constraints[j].description+constraints[j].namespace
+	})
+
+	return constraints
+}
+
+// balanceConstraint 计算使约束回到maxSkew以内需要驱逐的Pod
+func (s *RemovePodsViolatingTopologySpreadConstraintsStrategy) balanceConstraint(
+	constraint *topologyConstraint,
+	nodes []*v1.Node,
+	pods []*v1.Pod,
+	selected map[string]bool) []*v1.Pod {
+
+	// 每个带有拓扑标签的节点都属于一个域，没有匹配Pod的域计数为0
+	nodeDomains := make(map[string]string)
+	domains := make(map[string]*topologyDomain)
+	for _, node := range nodes {
+		value, ok := node.Labels[constraint.topologyKey]
+		if !ok {
+			continue
+		}
+		nodeDomains[node.Name] = value
+		if domains[value] == nil {
+			domains[value] = &topologyDomain{name: value}
+		}
+	}
+
+	if len(domains) < 2 {
+		return nil
+	}
+
+	for _, pod := range pods {
+		if !constraint.selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		domainName, ok := nodeDomains[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+
+		domain := domains[domainName]
+		domain.count++
+
+		if selected[utils.PodKey(pod)] {
+			continue
+		}
+		if canEvict, _ := s.context.Evictor.CanEvictPod(pod, s.Name()); canEvict {
+			domain.candidates = append(domain.candidates, pod)
+		}
+	}
+
+	var sortedDomains []*topologyDomain
+	for _, domain := range domains {
+		sort.SliceStable(domain.candidates, func(i, j int) bool {
+			return isLessImportant(domain.candidates[i], domain.candidates[j])
+		})
+		sortedDomains = append(sortedDomains, domain)
+	}
+
+	// 每次从Pod最多的域移走一个Pod（驱逐后由调度器放到Pod最少的域），直到偏差不超过maxSkew
+	var toEvict []*v1.Pod
+	for len(sortedDomains) >= 2 {
+		sort.Slice(sortedDomains, func(i, j int) bool {
+			if sortedDomains[i].count != sortedDomains[j].count {
+				return sortedDomains[i].count < sortedDomains[j].count
+			}
+			return sortedDomains[i].name < sortedDomains[j].name
+		})
+
+		smallest := sortedDomains[0]
+		largest := sortedDomains[len(sortedDomains)-1]
+		if largest.count-smallest.count <= constraint.maxSkew {
+			break
+		}
+
+		// 最大的域中没有可驱逐的Pod，不再考虑该域
+		if len(largest.candidates) == 0 {
+			klog.V(3).Infof("Domain %s=%s has no evictable pods left for constraint in namespace %s",
+				constraint.topologyKey, largest.name, constraint.namespace)
+			sortedDomains = sortedDomains[:len(sortedDomains)-1]
+			continue
+		}
+
+		pod := largest.candidates[0]
+		largest.candidates = largest.candidates[1:]
+		largest.count--
+		smallest.count++
+		toEvict = append(toEvict, pod)
+	}
+
+	if len(toEvict) > 0 {
+		klog.V(2).Infof("Selected %d pods in namespace %s to rebalance %s",
+			len(toEvict), constraint.namespace, constraint.description)
+	}
+
+	return toEvict
+}
@@ -5,9 +5,13 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	"lightweight-descheduler/pkg/config"
 	"lightweight-descheduler/pkg/eviction"
+	"lightweight-descheduler/pkg/snapshot"
+	"lightweight-descheduler/pkg/utils"
 )
 
 // Strategy 重调度策略接口
@@ -20,6 +24,9 @@ type Strategy interface {
 
 	// IsEnabled 检查策略是否启用
 	IsEnabled() bool
+
+	// Schedule 返回策略自身的驱逐时间安排，未配置时为nil
+	Schedule() *config.ScheduleConfig
 }
 
 // StrategyContext 策略执行上下文
@@ -27,11 +34,17 @@ type StrategyContext struct {
 	// Client Kubernetes客户端
 	Client kubernetes.Interface
 
+	// MetricsClient metrics.k8s.io客户端，可能为nil
+	MetricsClient metricsclientset.Interface
+
 	// Config 配置信息
 	Config *config.Config
 
 	// Evictor Pod驱逐器
 	Evictor eviction.PodEvictor
+
+	// Snapshot 本次循环的节点和Pod快照，由调度器在每次循环开始时设置
+	Snapshot *snapshot.Snapshot
 }
 
 // StrategyFactory 策略工厂
@@ -40,16 +53,22 @@ type StrategyFactory struct {
 }
 
 // NewStrategyFactory 创建策略工厂
-func NewStrategyFactory(client kubernetes.Interface, cfg *config.Config, evictor eviction.PodEvictor) *StrategyFactory {
+func NewStrategyFactory(client kubernetes.Interface, metricsClient metricsclientset.Interface, cfg *config.Config, evictor eviction.PodEvictor) *StrategyFactory {
 	return &StrategyFactory{
 		context: &StrategyContext{
-			Client:  client,
-			Config:  cfg,
-			Evictor: evictor,
+			Client:        client,
+			MetricsClient: metricsClient,
+			Config:        cfg,
+			Evictor:       evictor,
 		},
 	}
 }
 
+// Context 返回策略共享的执行上下文
+func (f *StrategyFactory) Context() *StrategyContext {
+	return f.context
+}
+
 // CreateStrategies 创建所有启用的策略
 func (f *StrategyFactory) CreateStrategies() []Strategy {
 	var strategies []Strategy
@@ -66,11 +85,89 @@ func (f *StrategyFactory) CreateStrategies() []Strategy {
 		strategies = append(strategies, NewLowNodeUtilizationStrategy(f.context))
 	}
 
+	// 高节点利用率（装箱）策略
+	if f.context.Config.Strategies.HighNodeUtilization != nil &&
+		f.context.Config.Strategies.HighNodeUtilization.Enabled {
+		strategies = append(strategies, NewHighNodeUtilizationStrategy(f.context))
+	}
+
 	// 重复Pod清理策略
 	if f.context.Config.Strategies.RemoveDuplicates != nil &&
 		f.context.Config.Strategies.RemoveDuplicates.Enabled {
 		strategies = append(strategies, NewRemoveDuplicatesStrategy(f.context))
 	}
 
+	// 违反节点亲和性的Pod清理策略
+	if f.context.Config.Strategies.RemovePodsViolatingNodeAffinity != nil &&
+		f.context.Config.Strategies.RemovePodsViolatingNodeAffinity.Enabled {
+		strategies = append(strategies, NewRemovePodsViolatingNodeAffinityStrategy(f.context))
+	}
+
+	// 不容忍节点污点的Pod清理策略
+	if f.context.Config.Strategies.RemovePodsViolatingNodeTaints != nil &&
+		f.context.Config.Strategies.RemovePodsViolatingNodeTaints.Enabled {
+		strategies = append(strategies, NewRemovePodsViolatingNodeTaintsStrategy(f.context))
+	}
+
+	// 违反拓扑分布约束的Pod清理策略
+	if f.context.Config.Strategies.RemovePodsViolatingTopologySpreadConstraints != nil &&
+		f.context.Config.Strategies.RemovePodsViolatingTopologySpreadConstraints.Enabled {
+		strategies = append(strategies, NewRemovePodsViolatingTopologySpreadConstraintsStrategy(f.context))
+	}
+
+	// 违反Pod间反亲和性的Pod清理策略
+	if f.context.Config.Strategies.RemovePodsViolatingInterPodAntiAffinity != nil &&
+		f.context.Config.Strategies.RemovePodsViolatingInterPodAntiAffinity.Enabled {
+		strategies = append(strategies, NewRemovePodsViolatingInterPodAntiAffinityStrategy(f.context))
+	}
+
+	// 长时间运行Pod回收策略
+	if f.context.Config.Strategies.PodLifeTime != nil &&
+		f.context.Config.Strategies.PodLifeTime.Enabled {
+		strategies = append(strategies, NewPodLifeTimeStrategy(f.context))
+	}
+
+	// 重启次数过多的Pod清理策略
+	if f.context.Config.Strategies.RemovePodsHavingTooManyRestarts != nil &&
+		f.context.Config.Strategies.RemovePodsHavingTooManyRestarts.Enabled {
+		strategies = append(strategies, NewRemovePodsHavingTooManyRestartsStrategy(f.context))
+	}
+
 	return strategies
 }
+
+// namespaceAllowed 按包含/排除列表检查是否应该处理此命名空间
+func namespaceAllowed(namespace string, included, excluded []string) bool {
+	// 如果指定了包含的命名空间，只处理这些命名空间
+	if len(included) > 0 {
+		return utils.Contains(included, namespace)
+	}
+
+	// 如果指定了排除的命名空间，不处理这些命名空间
+	if len(excluded) > 0 {
+		return !utils.Contains(excluded, namespace)
+	}
+
+	// 默认处理所有命名空间
+	return true
+}
+
+// hasExcludedOwnerKind 检查Pod的Owner类型是否在排除列表中
+func hasExcludedOwnerKind(pod *v1.Pod, excludeOwnerKinds []string) bool {
+	for _, ownerRef := range pod.OwnerReferences {
+		if utils.Contains(excludeOwnerKinds, ownerRef.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLessImportant 检查Pod a是否比Pod b更适合被驱逐：优先级更低，或优先级相同但更年轻
+func isLessImportant(a, b *v1.Pod) bool {
+	priorityA := corev1helpers.PodPriority(a)
+	priorityB := corev1helpers.PodPriority(b)
+	if priorityA != priorityB {
+		return priorityA < priorityB
+	}
+	return a.CreationTimestamp.After(b.CreationTimestamp.Time)
+}
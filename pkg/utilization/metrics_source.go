@@ -0,0 +1,112 @@
+package utilization
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// MetricsSource 基于metrics.k8s.io实际使用量的数据来源
+type MetricsSource struct {
+	client metricsclientset.Interface
+
+	// nodeUsage 按节点名称索引的CPU和内存使用量
+	nodeUsage map[string]map[v1.ResourceName]int64
+
+	// podUsage 按namespace/name索引的CPU和内存使用量
+	podUsage map[string]map[v1.ResourceName]int64
+}
+
+// NewMetricsSource 创建基于metrics-server的数据来源
+func NewMetricsSource(client metricsclientset.Interface) *MetricsSource {
+	return &MetricsSource{
+		client:    client,
+		nodeUsage: make(map[string]map[v1.ResourceName]int64),
+		podUsage:  make(map[string]map[v1.ResourceName]int64),
+	}
+}
+
+// Name 返回数据来源名称
+func (s *MetricsSource) Name() string {
+	return config.UtilizationSourceMetrics
+}
+
+// Refresh 从metrics-server拉取所有节点和Pod的最新使用量
+func (s *MetricsSource) Refresh(ctx context.Context) error {
+	nodeMetrics, err := s.client.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list node metrics: %v", err)
+	}
+
+	podMetrics, err := s.client.MetricsV1beta1().PodMetricses("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pod metrics: %v", err)
+	}
+
+	nodeUsage := make(map[string]map[v1.ResourceName]int64, len(nodeMetrics.Items))
+	for _, metrics := range nodeMetrics.Items {
+		usage := make(map[v1.ResourceName]int64)
+		addUsage(usage, metrics.Usage)
+		nodeUsage[metrics.Name] = usage
+	}
+
+	podUsage := make(map[string]map[v1.ResourceName]int64, len(podMetrics.Items))
+	for _, metrics := range podMetrics.Items {
+		usage := make(map[v1.ResourceName]int64)
+		for _, container := range metrics.Containers {
+			addUsage(usage, container.Usage)
+		}
+		podUsage[fmt.Sprintf("%s/%s", metrics.Namespace, metrics.Name)] = usage
+	}
+
+	s.nodeUsage = nodeUsage
+	s.podUsage = podUsage
+
+	klog.V(2).Infof("Loaded usage metrics for %d nodes and %d pods", len(nodeUsage), len(podUsage))
+	return nil
+}
+
+// NodeUtilization 按节点实际使用量计算利用率，Pod数量仍按节点上运行的Pod计算
+func (s *MetricsSource) NodeUtilization(node *v1.Node, pods []*v1.Pod) (*utils.NodeResourceUtilization, error) {
+	usage, ok := s.nodeUsage[node.Name]
+	if !ok {
+		return nil, fmt.Errorf("no usage metrics available for node %s", node.Name)
+	}
+
+	utilization := utils.CalculateNodeUtilization(node, pods)
+	utilization.SetUsage(usage)
+	return utilization, nil
+}
+
+// PodUsage 返回Pod的实际使用量，缺少指标时（如刚启动的Pod）回退为资源请求
+// metrics-server只提供CPU和内存，其他资源仍使用资源请求
+func (s *MetricsSource) PodUsage(pod *v1.Pod) map[v1.ResourceName]int64 {
+	requests := utils.PodRequests(pod)
+
+	usage, ok := s.podUsage[utils.PodKey(pod)]
+	if !ok {
+		klog.V(3).Infof("No usage metrics for pod %s/%s, falling back to requests", pod.Namespace, pod.Name)
+		return requests
+	}
+
+	for name, value := range usage {
+		requests[name] = value
+	}
+	return requests
+}
+
+// addUsage 把metrics中的CPU和内存用量累加到usage中
+func addUsage(usage map[v1.ResourceName]int64, resources v1.ResourceList) {
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		if quantity, ok := resources[name]; ok {
+			usage[name] += utils.QuantityValue(name, quantity)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package utilization
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+
+	"lightweight-descheduler/pkg/utils"
+)
+
+// newFakeMetricsClient 返回列出指定节点和Pod指标的metrics客户端
+// fake客户端的资源名称与对象类型不对应，直接用reactor返回列表
+func newFakeMetricsClient(nodes []metricsv1beta1.NodeMetrics, pods []metricsv1beta1.PodMetrics) *metricsfake.Clientset {
+	client := metricsfake.NewSimpleClientset()
+	client.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.NodeMetricsList{Items: nodes}, nil
+	})
+	client.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.PodMetricsList{Items: pods}, nil
+	})
+	return client
+}
+
+func newTestNode(name, cpu, memory string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+				v1.ResourcePods:   resource.MustParse("110"),
+			},
+		},
+	}
+}
+
+func newTestPod(name, nodeName, cpu, memory string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{
+			NodeName: nodeName,
+			Containers: []v1.Container{{
+				Name: "app",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse(cpu),
+						v1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func TestMetricsSourceUsesReportedUsage(t *testing.T) {
+	node := newTestNode("node-1", "4", "8Gi")
+	pod := newTestPod("web", "node-1", "1", "1Gi")
+
+	client := newFakeMetricsClient(
+		[]metricsv1beta1.NodeMetrics{{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Usage: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		}},
+		[]metricsv1beta1.PodMetrics{{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Containers: []metricsv1beta1.ContainerMetrics{{
+				Name: "app",
+				Usage: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("250m"),
+					v1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+			}},
+		}},
+	)
+
+	source := NewMetricsSource(client)
+	if err := source.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	utilization, err := source.NodeUtilization(node, []*v1.Pod{pod})
+	if err != nil {
+		t.Fatalf("NodeUtilization failed: %v", err)
+	}
+	if got := utilization.Percent[v1.ResourceCPU]; got != 50 {
+		t.Errorf("node cpu percent = %d, want 50", got)
+	}
+	if got := utilization.Percent[v1.ResourceMemory]; got != 25 {
+		t.Errorf("node memory percent = %d, want 25", got)
+	}
+
+	usage := source.PodUsage(pod)
+	if want := utils.QuantityValue(v1.ResourceCPU, resource.MustParse("250m")); usage[v1.ResourceCPU] != want {
+		t.Errorf("pod cpu usage = %d, want %d", usage[v1.ResourceCPU], want)
+	}
+	if want := utils.QuantityValue(v1.ResourceMemory, resource.MustParse("512Mi")); usage[v1.ResourceMemory] != want {
+		t.Errorf("pod memory usage = %d, want %d", usage[v1.ResourceMemory], want)
+	}
+}
+
+func TestMetricsSourceFallsBackToRequests(t *testing.T) {
+	node := newTestNode("node-1", "4", "8Gi")
+	pod := newTestPod("new", "node-1", "500m", "256Mi")
+
+	// 刚启动的Pod还没有指标
+	source := NewMetricsSource(newFakeMetricsClient(nil, nil))
+	if err := source.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	usage := source.PodUsage(pod)
+	requests := utils.PodRequests(pod)
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		if usage[name] != requests[name] {
+			t.Errorf("pod %s usage = %d, want requests %d", name, usage[name], requests[name])
+		}
+	}
+
+	// 没有指标的节点不能计算利用率
+	if _, err := source.NodeUtilization(node, []*v1.Pod{pod}); err == nil {
+		t.Errorf("NodeUtilization succeeded for a node without metrics")
+	}
+}
+
+func TestMetricsSourceRefreshError(t *testing.T) {
+	client := metricsfake.NewSimpleClientset()
+	client.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("metrics API not available")
+	})
+
+	source := NewMetricsSource(client)
+	if err := source.Refresh(context.Background()); err == nil {
+		t.Errorf("Refresh succeeded while the metrics API is unavailable")
+	}
+}
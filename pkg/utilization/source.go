@@ -0,0 +1,70 @@
+package utilization
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	"lightweight-descheduler/pkg/config"
+	"lightweight-descheduler/pkg/utils"
+)
+
+// Source 节点和Pod利用率数据来源
+type Source interface {
+	// Name 数据来源名称
+	Name() string
+
+	// Refresh 刷新数据，每次策略执行前调用一次
+	Refresh(ctx context.Context) error
+
+	// NodeUtilization 计算节点利用率，pods为节点上的所有Pod
+	NodeUtilization(node *v1.Node, pods []*v1.Pod) (*utils.NodeResourceUtilization, error)
+
+	// PodUsage 返回Pod按资源名称索引的用量，单位与utils.QuantityValue一致
+	PodUsage(pod *v1.Pod) map[v1.ResourceName]int64
+}
+
+// NewSource 根据配置的数据来源名称创建Source
+func NewSource(name string, metricsClient metricsclientset.Interface) (Source, error) {
+	switch name {
+	case "", config.UtilizationSourceRequests:
+		return NewRequestsSource(), nil
+	case config.UtilizationSourceMetrics:
+		if metricsClient == nil {
+			return nil, fmt.Errorf("utilization source %q requires a metrics client", name)
+		}
+		return NewMetricsSource(metricsClient), nil
+	default:
+		return nil, fmt.Errorf("unknown utilization source %q", name)
+	}
+}
+
+// RequestsSource 基于Pod资源请求的数据来源
+type RequestsSource struct{}
+
+// NewRequestsSource 创建基于资源请求的数据来源
+func NewRequestsSource() *RequestsSource {
+	return &RequestsSource{}
+}
+
+// Name 返回数据来源名称
+func (s *RequestsSource) Name() string {
+	return config.UtilizationSourceRequests
+}
+
+// Refresh 资源请求直接来自Pod规格，无需刷新
+func (s *RequestsSource) Refresh(_ context.Context) error {
+	return nil
+}
+
+// NodeUtilization 按Pod资源请求计算节点利用率
+func (s *RequestsSource) NodeUtilization(node *v1.Node, pods []*v1.Pod) (*utils.NodeResourceUtilization, error) {
+	return utils.CalculateNodeUtilization(node, pods), nil
+}
+
+// PodUsage 返回Pod的资源请求
+func (s *RequestsSource) PodUsage(pod *v1.Pod) map[v1.ResourceName]int64 {
+	return utils.PodRequests(pod)
+}
@@ -32,87 +32,209 @@ func RemoveFromSlice(slice []string, item string) []string {
 }
 
 // NodeResourceUtilization 节点资源利用率信息
+// 所有资源都按资源名称索引：CPU以毫核心计，pods为Pod数量，其他资源（内存、ephemeral-storage、
+// hugepages、扩展资源等）以基本单位计
 type NodeResourceUtilization struct {
-	NodeName      string
-	CPUUsage      int64 // CPU使用量（毫核心）
-	MemoryUsage   int64 // 内存使用量（字节）
-	PodsCount     int   // Pod数量
-	CPUPercent    int   // CPU使用率百分比
-	MemoryPercent int   // 内存使用率百分比
-	PodsPercent   int   // Pod数量使用率百分比
+	NodeName    string
+	Usage       map[v1.ResourceName]int64 // 资源使用量
+	Allocatable map[v1.ResourceName]int64 // 资源可分配量
+	Percent     map[v1.ResourceName]int   // 资源使用率百分比
 }
 
 // CalculateNodeUtilization 计算节点资源利用率
 func CalculateNodeUtilization(node *v1.Node, pods []*v1.Pod) *NodeResourceUtilization {
 	utilization := &NodeResourceUtilization{
-		NodeName: node.Name,
+		NodeName:    node.Name,
+		Usage:       make(map[v1.ResourceName]int64),
+		Allocatable: make(map[v1.ResourceName]int64),
+		Percent:     make(map[v1.ResourceName]int),
 	}
 
 	// 获取节点可分配资源
-	allocatable := node.Status.Allocatable
-	cpuAllocatable := allocatable[v1.ResourceCPU]
-	memoryAllocatable := allocatable[v1.ResourceMemory]
-	podsAllocatable := allocatable[v1.ResourcePods]
+	for name, quantity := range node.Status.Allocatable {
+		utilization.Allocatable[name] = QuantityValue(name, quantity)
+	}
 
 	// 计算Pod资源请求总和
-	var cpuRequests, memoryRequests resource.Quantity
-	podCount := 0
-
 	for _, pod := range pods {
 		// 跳过失败和成功的Pod
 		if pod.Status.Phase == v1.PodFailed || pod.Status.Phase == v1.PodSucceeded {
 			continue
 		}
 
-		podCount++
-		for _, container := range pod.Spec.Containers {
-			if cpu := container.Resources.Requests[v1.ResourceCPU]; !cpu.IsZero() {
-				cpuRequests.Add(cpu)
-			}
-			if memory := container.Resources.Requests[v1.ResourceMemory]; !memory.IsZero() {
-				memoryRequests.Add(memory)
-			}
+		for name, value := range PodRequests(pod) {
+			utilization.Usage[name] += value
 		}
+		utilization.Usage[v1.ResourcePods]++
 	}
 
-	utilization.CPUUsage = cpuRequests.MilliValue()
-	utilization.MemoryUsage = memoryRequests.Value()
-	utilization.PodsCount = podCount
+	utilization.updatePercents()
+	return utilization
+}
 
-	// 计算使用率百分比
-	if !cpuAllocatable.IsZero() {
-		utilization.CPUPercent = int((cpuRequests.MilliValue() * 100) / cpuAllocatable.MilliValue())
+// RemovePod 从利用率中扣除一个Pod的资源用量，用于模拟驱逐后的节点状态
+func (u *NodeResourceUtilization) RemovePod(usage map[v1.ResourceName]int64) {
+	for name, value := range usage {
+		if name == v1.ResourcePods {
+			continue
+		}
+		u.Usage[name] -= value
 	}
-	if !memoryAllocatable.IsZero() {
-		utilization.MemoryPercent = int((memoryRequests.Value() * 100) / memoryAllocatable.Value())
+	u.Usage[v1.ResourcePods]--
+	u.updatePercents()
+}
+
+// SetUsage 使用给定的用量替换对应资源的当前值，用于接入实际使用量数据
+func (u *NodeResourceUtilization) SetUsage(usage map[v1.ResourceName]int64) {
+	for name, value := range usage {
+		u.Usage[name] = value
 	}
-	if !podsAllocatable.IsZero() {
-		utilization.PodsPercent = int((int64(podCount) * 100) / podsAllocatable.Value())
+	u.updatePercents()
+}
+
+// Summary 返回指定资源的使用率摘要，如 "cpu=20%, memory=35%"
+func (u *NodeResourceUtilization) Summary(resources []v1.ResourceName) string {
+	parts := make([]string, 0, len(resources))
+	for _, name := range resources {
+		parts = append(parts, fmt.Sprintf("%s=%d%%", name, u.Percent[name]))
 	}
+	return strings.Join(parts, ", ")
+}
 
-	return utilization
+// updatePercents 根据使用量和可分配量重新计算使用率百分比
+// 节点没有的资源（如非GPU节点上的nvidia.com/gpu）使用率按0计算
+func (u *NodeResourceUtilization) updatePercents() {
+	percent := make(map[v1.ResourceName]int, len(u.Usage))
+	for name, allocatable := range u.Allocatable {
+		if allocatable > 0 {
+			percent[name] = int((u.Usage[name] * 100) / allocatable)
+		}
+	}
+	u.Percent = percent
 }
 
-// IsNodeUnderUtilized 检查节点是否利用率不足
-func IsNodeUnderUtilized(utilization *NodeResourceUtilization, thresholds map[string]int) bool {
-	cpuThreshold := thresholds["cpu"]
-	memoryThreshold := thresholds["memory"]
-	podsThreshold := thresholds["pods"]
+// QuantityValue 把资源数量转换为整数，CPU以毫核心计，其他资源以基本单位计
+func QuantityValue(name v1.ResourceName, quantity resource.Quantity) int64 {
+	if name == v1.ResourceCPU {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}
+
+// PodRequests 获取Pod所有资源的有效请求量，单位与QuantityValue一致
+// 计算方式与kube-scheduler一致：
+//   - 普通容器的请求量求和
+//   - 重启策略为Always的边车初始化容器与普通容器同时运行，请求量累加到总量中
+//   - 普通初始化容器依次运行，运行时还需加上在它之前启动的边车容器，取其中最大值与总量比较，取较大者
+//   - 最后加上Pod的运行时开销（spec.overhead，如Kata、gVisor）
+func PodRequests(pod *v1.Pod) map[v1.ResourceName]int64 {
+	requests := make(map[v1.ResourceName]int64)
+	for _, container := range pod.Spec.Containers {
+		addResourceList(requests, container.Resources.Requests)
+	}
+
+	// sidecarRequests 已启动的边车容器请求量之和
+	sidecarRequests := make(map[v1.ResourceName]int64)
+	initRequests := make(map[v1.ResourceName]int64)
+	for _, container := range pod.Spec.InitContainers {
+		containerRequests := make(map[v1.ResourceName]int64)
+		addResourceList(containerRequests, container.Resources.Requests)
 
-	return utilization.CPUPercent < cpuThreshold &&
-		utilization.MemoryPercent < memoryThreshold &&
-		utilization.PodsPercent < podsThreshold
+		if container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			addRequests(requests, containerRequests)
+			addRequests(sidecarRequests, containerRequests)
+			containerRequests = copyRequests(sidecarRequests)
+		} else {
+			addRequests(containerRequests, sidecarRequests)
+		}
+
+		maxRequests(initRequests, containerRequests)
+	}
+	maxRequests(requests, initRequests)
+
+	addResourceList(requests, pod.Spec.Overhead)
+	return requests
 }
 
-// IsNodeOverUtilized 检查节点是否利用率过高
-func IsNodeOverUtilized(utilization *NodeResourceUtilization, thresholds map[string]int) bool {
-	cpuThreshold := thresholds["cpu"]
-	memoryThreshold := thresholds["memory"]
-	podsThreshold := thresholds["pods"]
+// addResourceList 把资源列表累加到requests中
+func addResourceList(requests map[v1.ResourceName]int64, list v1.ResourceList) {
+	for name, quantity := range list {
+		requests[name] += QuantityValue(name, quantity)
+	}
+}
 
-	return utilization.CPUPercent > cpuThreshold ||
-		utilization.MemoryPercent > memoryThreshold ||
-		utilization.PodsPercent > podsThreshold
+// addRequests 把other累加到requests中
+func addRequests(requests, other map[v1.ResourceName]int64) {
+	for name, value := range other {
+		requests[name] += value
+	}
+}
+
+// maxRequests 对每种资源取requests和other中的较大值，结果写入requests
+func maxRequests(requests, other map[v1.ResourceName]int64) {
+	for name, value := range other {
+		if value > requests[name] {
+			requests[name] = value
+		}
+	}
+}
+
+// copyRequests 复制资源请求量
+func copyRequests(requests map[v1.ResourceName]int64) map[v1.ResourceName]int64 {
+	copied := make(map[v1.ResourceName]int64, len(requests))
+	for name, value := range requests {
+		copied[name] = value
+	}
+	return copied
+}
+
+// GetPodRequests 获取Pod的CPU（毫核心）和内存（字节）有效请求量
+func GetPodRequests(pod *v1.Pod) (int64, int64) {
+	requests := PodRequests(pod)
+	return requests[v1.ResourceCPU], requests[v1.ResourceMemory]
+}
+
+// legacyThresholdResources 旧配置格式中固定的三个阈值字段，与旧版本一致，未配置时按0处理
+var legacyThresholdResources = []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods}
+
+// ThresholdResources 返回参与判断的资源名称，按名称排序
+// 包括配置的资源和未配置的cpu、memory、pods（阈值按0处理）
+func ThresholdResources(thresholds map[string]int) []v1.ResourceName {
+	resources := make([]v1.ResourceName, 0, len(thresholds)+len(legacyThresholdResources))
+	for name := range thresholds {
+		resources = append(resources, v1.ResourceName(name))
+	}
+	for _, name := range legacyThresholdResources {
+		if _, ok := thresholds[string(name)]; !ok {
+			resources = append(resources, name)
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i] < resources[j]
+	})
+	return resources
+}
+
+// IsNodeUnderUtilized 检查节点是否利用率不足：所有参与判断的资源都低于阈值
+// 未配置的cpu、memory、pods阈值按0处理，因此缺少其中任一个时节点不会被当作低利用率节点
+func IsNodeUnderUtilized(utilization *NodeResourceUtilization, thresholds map[string]int) bool {
+	for _, name := range ThresholdResources(thresholds) {
+		if utilization.Percent[name] >= thresholds[string(name)] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNodeOverUtilized 检查节点是否利用率过高：任一参与判断的资源高于阈值
+// 未配置的cpu、memory、pods阈值按0处理
+func IsNodeOverUtilized(utilization *NodeResourceUtilization, thresholds map[string]int) bool {
+	for _, name := range ThresholdResources(thresholds) {
+		if utilization.Percent[name] > thresholds[string(name)] {
+			return true
+		}
+	}
+	return false
 }
 
 // PodKey 生成Pod的唯一标识